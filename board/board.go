@@ -0,0 +1,103 @@
+// Package board holds the pieces shared by the travelers simulations:
+// the toroidal board, positions with wrap-around moves, traces and the
+// printer that reports them.
+package board
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+)
+
+// Position on the board
+type Position struct {
+	X int // X coordinate, range: 0 to Width - 1
+	Y int // Y coordinate, range: 0 to Height - 1
+}
+
+// Direction of a single step
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+// Board is a 2D board with torus topology
+type Board struct {
+	Width  int
+	Height int
+
+	cells   [][]*Cell          // cell servers, nil until ServeCells is called
+	stop    context.CancelFunc // stops the cell servers
+	servers sync.WaitGroup     // running cell servers
+	journal Journal            // orders the cell operations of actors, may be nil
+}
+
+// New returns a board of the given size
+func New(width, height int) *Board {
+	return &Board{Width: width, Height: height}
+}
+
+// MoveDown moves the position down on the board (with wrap-around)
+func (b *Board) MoveDown(p Position) Position {
+	return Position{X: p.X, Y: (p.Y + 1) % b.Height}
+}
+
+// MoveUp moves the position up on the board (with wrap-around)
+func (b *Board) MoveUp(p Position) Position {
+	return Position{X: p.X, Y: (p.Y + b.Height - 1) % b.Height}
+}
+
+// MoveRight moves the position right on the board (with wrap-around)
+func (b *Board) MoveRight(p Position) Position {
+	return Position{X: (p.X + 1) % b.Width, Y: p.Y}
+}
+
+// MoveLeft moves the position left on the board (with wrap-around)
+func (b *Board) MoveLeft(p Position) Position {
+	return Position{X: (p.X + b.Width - 1) % b.Width, Y: p.Y}
+}
+
+// Move moves the position one step in the given direction
+func (b *Board) Move(p Position, d Direction) Position {
+	switch d {
+	case Up:
+		return b.MoveUp(p)
+	case Down:
+		return b.MoveDown(p)
+	case Left:
+		return b.MoveLeft(p)
+	default:
+		return b.MoveRight(p)
+	}
+}
+
+// RandomStep moves the position one step in a random direction
+func (b *Board) RandomStep(p Position, r *rand.Rand) Position {
+	return b.Move(p, Direction(r.Intn(4)))
+}
+
+// Detour moves the position one step in a random direction that does not
+// lead to blocked, if there is one
+func (b *Board) Detour(p, blocked Position, r *rand.Rand) Position {
+	first := r.Intn(4)
+	for i := 0; i < 4; i++ {
+		if q := b.Move(p, Direction((first+i)%4)); q != blocked {
+			return q
+		}
+	}
+	return blocked
+}
+
+// RandomPosition returns a uniformly chosen position on the board
+func (b *Board) RandomPosition(r *rand.Rand) Position {
+	return Position{X: r.Intn(b.Width), Y: r.Intn(b.Height)}
+}
+
+// Hidden is the off-board position used for entities that left the board
+func (b *Board) Hidden() Position {
+	return Position{X: b.Width, Y: b.Height}
+}
@@ -0,0 +1,183 @@
+package board
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Occupant tells who is standing on a cell
+type Occupant int
+
+const (
+	Empty    Occupant = iota
+	Traveler          // a legal traveler, it never gives way
+	Wild              // a wild tenant, it can be asked to relocate
+)
+
+// Status is the answer of a cell server to a request
+type Status struct {
+	CanOccupy   bool
+	Occupant    Occupant
+	WildMoveReq chan chan bool  // only for Wild, asks the tenant to relocate
+	WildGone    <-chan struct{} // only for Wild, closed once the tenant stopped answering
+	IsTrap      bool
+	TrapId      int
+	Id          int // ID of the occupant, unless the cell is Empty
+}
+
+type occupant struct {
+	typ         Occupant
+	id          int
+	wildMoveReq chan chan bool
+	wildGone    <-chan struct{}
+}
+
+type trap struct {
+	id int
+}
+
+// A Cell is a stateful goroutine that serves requests for one square.
+// Once its server stopped, requests get the zero Status and the other
+// operations do nothing.
+type Cell struct {
+	reqCh    chan chan Status
+	occupyCh chan occupant
+	freeCh   chan struct{}
+	trapCh   chan trap
+	done     <-chan struct{}
+	refused  atomic.Int64 // requests answered while the cell was taken
+}
+
+// NewCell returns a cell whose server runs until ctx is done; the server
+// must be started with Serve
+func NewCell(ctx context.Context) *Cell {
+	return &Cell{
+		reqCh:    make(chan chan Status),
+		occupyCh: make(chan occupant),
+		freeCh:   make(chan struct{}),
+		trapCh:   make(chan trap),
+		done:     ctx.Done(),
+	}
+}
+
+// Serve runs the server of the cell until its context is done
+func (c *Cell) Serve() {
+	occ := occupant{typ: Empty}
+	isTrap, trapId := false, 0
+	for {
+		select {
+		case <-c.done:
+			return
+		case resp := <-c.reqCh:
+			if occ.typ != Empty {
+				c.refused.Add(1)
+			}
+			resp <- Status{
+				CanOccupy:   occ.typ == Empty,
+				Occupant:    occ.typ,
+				WildMoveReq: occ.wildMoveReq,
+				WildGone:    occ.wildGone,
+				IsTrap:      isTrap,
+				TrapId:      trapId,
+				Id:          occ.id,
+			}
+		case o := <-c.occupyCh:
+			occ = o
+		case <-c.freeCh:
+			occ = occupant{typ: Empty}
+		case t := <-c.trapCh:
+			isTrap, trapId = true, t.id
+		}
+	}
+}
+
+// Request checks if cell is free or occupied
+func (c *Cell) Request() Status {
+	respCh := make(chan Status)
+	select {
+	case c.reqCh <- respCh:
+		return <-respCh
+	case <-c.done:
+		return Status{}
+	}
+}
+
+// Occupy marks cell occupied by the traveler with the given ID
+func (c *Cell) Occupy(id int) {
+	c.occupy(occupant{typ: Traveler, id: id})
+}
+
+// OccupyWild marks cell occupied by a wild tenant, providing its move
+// request channel and a channel it closes when it no longer answers
+func (c *Cell) OccupyWild(id int, moveReq chan chan bool, gone <-chan struct{}) {
+	c.occupy(occupant{typ: Wild, id: id, wildMoveReq: moveReq, wildGone: gone})
+}
+
+// Free marks cell free
+func (c *Cell) Free() {
+	select {
+	case c.freeCh <- struct{}{}:
+	case <-c.done:
+	}
+}
+
+// SetTrap turns the cell into a trap with the given ID
+func (c *Cell) SetTrap(id int) {
+	select {
+	case c.trapCh <- trap{id: id}:
+	case <-c.done:
+	}
+}
+
+func (c *Cell) occupy(o occupant) {
+	select {
+	case c.occupyCh <- o:
+	case <-c.done:
+	}
+}
+
+// ServeCells starts one server per cell of the board, running until ctx
+// is done or the board is closed
+func (b *Board) ServeCells(ctx context.Context) {
+	ctx, b.stop = context.WithCancel(ctx)
+	b.cells = make([][]*Cell, b.Width)
+	for x := 0; x < b.Width; x++ {
+		b.cells[x] = make([]*Cell, b.Height)
+		for y := 0; y < b.Height; y++ {
+			c := NewCell(ctx)
+			b.cells[x][y] = c
+			b.servers.Add(1)
+			go func() {
+				defer b.servers.Done()
+				c.Serve()
+			}()
+		}
+	}
+}
+
+// Close stops the cell servers and waits until all of them returned
+func (b *Board) Close() {
+	if b.stop != nil {
+		b.stop()
+	}
+	b.servers.Wait()
+}
+
+// Cell returns the server of the cell at p
+func (b *Board) Cell(p Position) *Cell {
+	return b.cells[p.X][p.Y]
+}
+
+// Contention returns how many requests every cell served so far found it
+// taken, leaving out the cells that never did
+func (b *Board) Contention() Contention {
+	c := make(Contention)
+	for x, column := range b.cells {
+		for y, cell := range column {
+			if n := cell.refused.Load(); n > 0 {
+				c[Position{X: x, Y: y}] = n
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,121 @@
+package board
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// Config holds the run-time parameters of a travelers simulation
+type Config struct {
+	// Travelers moving on the board
+	NrOfTravelers int
+
+	MinSteps int
+	MaxSteps int
+
+	MinDelay time.Duration
+	MaxDelay time.Duration
+
+	// 2D Board with torus topology
+	BoardWidth  int
+	BoardHeight int
+
+	// Wild traveler parameters
+	NrOfWildSpawns  int
+	WildMinLifespan time.Duration
+	WildMaxLifespan time.Duration
+
+	// Traps
+	NrOfTraps     int
+	TrapBlockTime time.Duration
+}
+
+// MaxTravelers is the number of distinct traveler symbols 'A'..'Z'
+const MaxTravelers = 26
+
+// DefaultConfig returns the parameters used by the original assignments
+func DefaultConfig() Config {
+	return Config{
+		NrOfTravelers:   15,
+		MinSteps:        10,
+		MaxSteps:        100,
+		MinDelay:        10 * time.Millisecond,
+		MaxDelay:        50 * time.Millisecond,
+		BoardWidth:      15,
+		BoardHeight:     15,
+		NrOfWildSpawns:  10,
+		WildMinLifespan: 500 * time.Millisecond,
+		WildMaxLifespan: 2000 * time.Millisecond,
+		NrOfTraps:       15,
+		TrapBlockTime:   500 * time.Millisecond,
+	}
+}
+
+// RegisterFlags defines the flags shared by every travelers simulation
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.NrOfTravelers, "travelers", c.NrOfTravelers, "number of travelers")
+	fs.IntVar(&c.BoardWidth, "width", c.BoardWidth, "board width")
+	fs.IntVar(&c.BoardHeight, "height", c.BoardHeight, "board height")
+	fs.IntVar(&c.MinSteps, "min-steps", c.MinSteps, "minimal number of steps of a traveler")
+	fs.IntVar(&c.MaxSteps, "max-steps", c.MaxSteps, "maximal number of steps of a traveler")
+	fs.DurationVar(&c.MinDelay, "min-delay", c.MinDelay, "minimal delay between steps")
+	fs.DurationVar(&c.MaxDelay, "max-delay", c.MaxDelay, "maximal delay between steps")
+}
+
+// RegisterWildFlags defines the wild tenant flags
+func (c *Config) RegisterWildFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.NrOfWildSpawns, "wild-spawns", c.NrOfWildSpawns, "total number of wild tenant spawns")
+	fs.DurationVar(&c.WildMinLifespan, "wild-min-lifespan", c.WildMinLifespan, "minimal lifespan of a wild tenant")
+	fs.DurationVar(&c.WildMaxLifespan, "wild-max-lifespan", c.WildMaxLifespan, "maximal lifespan of a wild tenant")
+}
+
+// RegisterTrapFlags defines the trap flags
+func (c *Config) RegisterTrapFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.NrOfTraps, "traps", c.NrOfTraps, "number of traps")
+	fs.DurationVar(&c.TrapBlockTime, "trap-block", c.TrapBlockTime, "how long a trap holds its victim")
+}
+
+// Validate checks that the parameters make sense for any simulation
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	check(c.NrOfTravelers >= 1 && c.NrOfTravelers <= MaxTravelers,
+		"travelers must be in 1..%d, got %d", MaxTravelers, c.NrOfTravelers)
+	check(c.BoardWidth >= 1, "width must be positive, got %d", c.BoardWidth)
+	check(c.BoardHeight >= 1, "height must be positive, got %d", c.BoardHeight)
+	check(c.MinSteps >= 0 && c.MinSteps < c.MaxSteps,
+		"steps must satisfy 0 <= min-steps < max-steps, got %d and %d", c.MinSteps, c.MaxSteps)
+	check(c.MinDelay >= 0 && c.MinDelay < c.MaxDelay,
+		"delays must satisfy 0 <= min-delay < max-delay, got %v and %v", c.MinDelay, c.MaxDelay)
+	check(c.NrOfWildSpawns >= 0, "wild-spawns must not be negative, got %d", c.NrOfWildSpawns)
+	check(c.WildMinLifespan >= 0 && c.WildMinLifespan < c.WildMaxLifespan,
+		"lifespans must satisfy 0 <= wild-min-lifespan < wild-max-lifespan, got %v and %v",
+		c.WildMinLifespan, c.WildMaxLifespan)
+	check(c.NrOfTraps >= 0, "traps must not be negative, got %d", c.NrOfTraps)
+	check(c.TrapBlockTime >= 0, "trap-block must not be negative, got %v", c.TrapBlockTime)
+	return errors.Join(errs...)
+}
+
+// ValidateExclusive additionally checks that every traveler, wild tenant
+// and trap can get a cell of its own
+func (c *Config) ValidateExclusive() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if n := c.NrOfTravelers + c.NrOfWildSpawns + c.NrOfTraps; n > c.BoardWidth*c.BoardHeight {
+		return fmt.Errorf("%d travelers, wild tenants and traps do not fit on a %dx%d board",
+			n, c.BoardWidth, c.BoardHeight)
+	}
+	return nil
+}
+
+// Board returns an empty board of the configured size
+func (c *Config) Board() *Board {
+	return New(c.BoardWidth, c.BoardHeight)
+}
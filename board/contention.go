@@ -0,0 +1,56 @@
+package board
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Contention counts the refused requests of every cell, see
+// Board.Contention; the programs print it as the CONTENTION parameter
+type Contention map[Position]int64
+
+// Param returns the CONTENTION parameter, "x:y:n" for every cell in
+// column order, separated by commas
+func (c Contention) Param() string {
+	cells := make([]Position, 0, len(c))
+	for p := range c {
+		cells = append(cells, p)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].X != cells[j].X {
+			return cells[i].X < cells[j].X
+		}
+		return cells[i].Y < cells[j].Y
+	})
+	fields := make([]string, len(cells))
+	for i, p := range cells {
+		fields[i] = fmt.Sprintf("%d:%d:%d", p.X, p.Y, c[p])
+	}
+	return strings.Join(fields, ",")
+}
+
+// ParseContention reads the CONTENTION parameter
+func ParseContention(s string) (Contention, error) {
+	c := make(Contention)
+	if s == "" {
+		return c, nil
+	}
+	for _, f := range strings.Split(s, ",") {
+		parts := strings.Split(f, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("contention %q: want x:y:n", f)
+		}
+		var v [3]int64
+		for i, part := range parts {
+			n, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("contention %q: %v", f, err)
+			}
+			v[i] = n
+		}
+		c[Position{X: int(v[0]), Y: int(v[1])}] += v[2]
+	}
+	return c, nil
+}
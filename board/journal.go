@@ -0,0 +1,60 @@
+package board
+
+import "fmt"
+
+// Journal orders the operations on the cells, so that the order in which
+// the cell servers serve them can be recorded and replayed; *sched.Journal
+// implements it
+type Journal interface {
+	Do(who int, event string, op func())
+}
+
+// SetJournal makes every cell operation of an Actor go through j
+func (b *Board) SetJournal(j Journal) {
+	b.journal = j
+}
+
+// Actor is the view of the cells of one traveler, wild tenant or trap
+type Actor struct {
+	b  *Board
+	id int
+}
+
+// Actor returns the view of the cells of the participant with the given ID
+func (b *Board) Actor(id int) Actor {
+	return Actor{b: b, id: id}
+}
+
+func (a Actor) do(event string, p Position, op func()) {
+	if a.b.journal == nil {
+		op()
+		return
+	}
+	a.b.journal.Do(a.id, fmt.Sprintf("%s %d %d", event, p.X, p.Y), op)
+}
+
+// Request asks the server of the cell at p for its status
+func (a Actor) Request(p Position) (s Status) {
+	a.do("request", p, func() { s = a.b.Cell(p).Request() })
+	return s
+}
+
+// Occupy puts the actor on the cell at p as a traveler
+func (a Actor) Occupy(p Position) {
+	a.do("occupy", p, func() { a.b.Cell(p).Occupy(a.id) })
+}
+
+// OccupyWild puts the actor on the cell at p as a wild tenant
+func (a Actor) OccupyWild(p Position, moveReq chan chan bool, gone <-chan struct{}) {
+	a.do("occupy-wild", p, func() { a.b.Cell(p).OccupyWild(a.id, moveReq, gone) })
+}
+
+// Free leaves the cell at p
+func (a Actor) Free(p Position) {
+	a.do("free", p, a.b.Cell(p).Free)
+}
+
+// SetTrap turns the cell at p into a trap with the actor's ID
+func (a Actor) SetTrap(p Position) {
+	a.do("trap", p, func() { a.b.Cell(p).SetTrap(a.id) })
+}
@@ -0,0 +1,208 @@
+package board
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Action is what a traveler does after failing to move
+type Action int
+
+const (
+	GiveUp   Action = iota // stop for good, in lowercase
+	Retry                  // try the same cell again after the delay
+	Redirect               // try another neighbour after the delay
+)
+
+// Conflict describes a move a traveler failed to make
+type Conflict struct {
+	Traveler int        // the blocked traveler
+	Holder   int        // the traveler holding the wanted cell, -1 if not known
+	Attempts int        // failed attempts at this move, 1 for the first one
+	Rand     *rand.Rand // random numbers of the blocked traveler
+	// Cycle are the travelers of the waiting cycle the move would close,
+	// starting with Traveler, nil unless the program finds cycles
+	Cycle []int
+}
+
+// Resolution is the decision of a policy about a conflict
+type Resolution struct {
+	Action Action
+	Delay  time.Duration
+	Wound  bool  // the holder must give up, see Resolver.Aborted
+	Abort  []int // other travelers that must give up to break the cycle
+}
+
+// Policy decides what a traveler does when a move request fails. The
+// travelers are ranked by ID: the lower the ID, the older the traveler.
+type Policy interface {
+	Resolve(c Conflict) Resolution
+}
+
+// PolicyFunc turns a function into a Policy
+type PolicyFunc func(c Conflict) Resolution
+
+func (f PolicyFunc) Resolve(c Conflict) Resolution { return f(c) }
+
+// MaxAttempts is the number of failed attempts at one move after which
+// every policy gives up, a blocked traveler may be stuck for good
+const MaxAttempts = 5
+
+// NewPolicy returns the policy with the given name; delay is the time a
+// traveler waits before trying again, the maximal delay between steps
+func NewPolicy(name string, delay time.Duration) (Policy, error) {
+	retry := func(c Conflict, r Resolution) Resolution {
+		if c.Attempts >= MaxAttempts {
+			return Resolution{Action: GiveUp}
+		}
+		return r
+	}
+	switch name {
+	case "give-up":
+		return PolicyFunc(func(Conflict) Resolution { return Resolution{Action: GiveUp} }), nil
+	case "backoff":
+		// random exponential back-off
+		return PolicyFunc(func(c Conflict) Resolution {
+			d := time.Duration(c.Rand.Int63n(int64(delay) << (c.Attempts - 1)))
+			return retry(c, Resolution{Action: Retry, Delay: d})
+		}), nil
+	case "redirect":
+		return PolicyFunc(func(c Conflict) Resolution {
+			return retry(c, Resolution{Action: Redirect})
+		}), nil
+	case "wait-die":
+		// an older traveler waits for a younger one, a younger one dies.
+		// A cycle an older traveler closes was closed by a younger one
+		// waiting for an older one before: the youngest of the cycle does
+		// so, it dies now and frees its cell.
+		return PolicyFunc(func(c Conflict) Resolution {
+			if c.Holder >= 0 && c.Traveler > c.Holder {
+				return Resolution{Action: GiveUp}
+			}
+			res := Resolution{Action: Retry, Delay: delay}
+			if youngest := slices.Max(append([]int{c.Traveler}, c.Cycle...)); youngest != c.Traveler {
+				res.Abort = []int{youngest}
+			}
+			return retry(c, res)
+		}), nil
+	case "wound-wait":
+		// an older traveler wounds a younger one, a younger one waits
+		return PolicyFunc(func(c Conflict) Resolution {
+			wound := c.Holder >= 0 && c.Traveler < c.Holder
+			return retry(c, Resolution{Action: Retry, Delay: delay, Wound: wound})
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown policy %q, use one of %s", name, strings.Join(Policies, ", "))
+}
+
+// Policies are the names accepted by NewPolicy
+var Policies = []string{"give-up", "backoff", "redirect", "wait-die", "wound-wait"}
+
+// RegisterPolicy defines the -policy flag on fs
+func RegisterPolicy(fs *flag.FlagSet) *string {
+	return fs.String("policy", Policies[0], "what a blocked traveler does: "+strings.Join(Policies, ", "))
+}
+
+// Stats counts what the travelers did under a policy
+type Stats struct {
+	Steps     int // moves made
+	Conflicts int // moves that failed
+	Retries   int
+	Redirects int
+	Wounds    int // travelers wounded by an older one
+	Aborted   int // travelers that gave up before their last step
+}
+
+// Params returns the statistics as parameters of a trace
+func (s Stats) Params() map[string]string {
+	return map[string]string{
+		"STEPS":     strconv.Itoa(s.Steps),
+		"CONFLICTS": strconv.Itoa(s.Conflicts),
+		"RETRIES":   strconv.Itoa(s.Retries),
+		"REDIRECTS": strconv.Itoa(s.Redirects),
+		"WOUNDS":    strconv.Itoa(s.Wounds),
+		"ABORTED":   strconv.Itoa(s.Aborted),
+	}
+}
+
+// Resolver applies a policy on behalf of the travelers and keeps its
+// statistics; it is safe for concurrent use
+type Resolver struct {
+	Name   string
+	policy Policy
+
+	mu      sync.Mutex
+	aborted map[int]bool // wounded, or dying to break a cycle
+	stats   Stats
+}
+
+// NewResolver returns a resolver applying the named policy, see NewPolicy
+func NewResolver(name string, delay time.Duration) (*Resolver, error) {
+	p, err := NewPolicy(name, delay)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{Name: name, policy: p, aborted: make(map[int]bool)}, nil
+}
+
+// Resolve decides what to do about a failed move; an aborted traveler
+// always gives up
+func (r *Resolver) Resolve(c Conflict) Resolution {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Conflicts++
+	if r.aborted[c.Traveler] {
+		return Resolution{Action: GiveUp}
+	}
+	res := r.policy.Resolve(c)
+	if res.Wound && !r.aborted[c.Holder] {
+		r.aborted[c.Holder] = true
+		r.stats.Wounds++
+	}
+	for _, id := range res.Abort {
+		r.aborted[id] = true
+	}
+	switch res.Action {
+	case Retry:
+		r.stats.Retries++
+	case Redirect:
+		r.stats.Redirects++
+	}
+	return res
+}
+
+// Aborted reports whether an older traveler wounded traveler id, or the
+// policy chose it to break a cycle, so that it must give up; the
+// travelers check it before every step and while they wait
+func (r *Resolver) Aborted(id int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.aborted[id]
+}
+
+// Moved counts a step made
+func (r *Resolver) Moved() {
+	r.mu.Lock()
+	r.stats.Steps++
+	r.mu.Unlock()
+}
+
+// GaveUp counts a traveler that stopped before its last step
+func (r *Resolver) GaveUp() {
+	r.mu.Lock()
+	r.stats.Aborted++
+	r.mu.Unlock()
+}
+
+// Stats returns the statistics so far
+func (r *Resolver) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
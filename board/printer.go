@@ -0,0 +1,48 @@
+package board
+
+import "io"
+
+// FormatFunc writes a single trace line, see trace.Format
+type FormatFunc func(w io.Writer, t Trace)
+
+// Printer collects and prints reports of traces
+type Printer struct {
+	out     io.Writer
+	format  FormatFunc
+	reports chan TracesSequence // Channel for trace reports
+	done    chan struct{}
+}
+
+// NewPrinter returns a printer writing to out; buffer is the capacity of
+// the report channel
+func NewPrinter(out io.Writer, format FormatFunc, buffer int) *Printer {
+	return &Printer{
+		out:     out,
+		format:  format,
+		reports: make(chan TracesSequence, buffer),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the printer goroutine
+func (p *Printer) Start() {
+	go func() {
+		defer close(p.done)
+		for seq := range p.reports {
+			for _, t := range seq.Traces {
+				p.format(p.out, t)
+			}
+		}
+	}()
+}
+
+// Report sends traces to the Printer's channel
+func (p *Printer) Report(seq TracesSequence) {
+	p.reports <- seq
+}
+
+// Stop closes the Printer's channel and waits until every report is printed
+func (p *Printer) Stop() {
+	close(p.reports)
+	<-p.done
+}
@@ -0,0 +1,33 @@
+package board
+
+import "time"
+
+// Event tells what a trace records
+type Event string
+
+const (
+	Move        Event = "move"         // appeared on the board or stepped to a new cell
+	Blocked     Event = "blocked"      // found no free neighbour to relocate to, or waits for a slow neighbour
+	Delayed     Event = "delayed"      // held back by the deadlock-avoidance rule
+	Deadlock    Event = "deadlock"     // gave up waiting for a cell, lowercase symbol
+	Trapped     Event = "trapped"      // entered a trap, lowercase or '*' symbol
+	Relocated   Event = "relocated"    // a wild tenant made room for a traveler
+	Expired     Event = "expired"      // a wild tenant's life ended, hidden position
+	Finished    Event = "finished"     // a traveler finished and freed its cell, hidden position
+	StateChange Event = "state-change" // a trap or a lista3 process changed state
+)
+
+// Trace of a traveler at one moment
+type Trace struct {
+	TimeStamp time.Duration // Time stamp of the trace
+	Id        int           // Traveler ID
+	Position  Position      // Position of the traveler
+	Symbol    rune          // Symbol representing the traveler
+	Event     Event         // What happened, empty when read from a text trace
+}
+
+// TracesSequence is the message sent from a traveler to the printer
+type TracesSequence struct {
+	Id     int
+	Traces []Trace
+}
@@ -0,0 +1,72 @@
+// Package check verifies the invariants the simulations promise, on
+// traces read with the trace package. Every check returns the violations
+// it found, so it can be called from a test as well as from the
+// tracecheck command.
+package check
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// CriticalSection is the row label of the critical section in the
+// lista3 traces
+const CriticalSection = "CRITICAL_SECTION"
+
+// Overlap is a stretch of time two processes spent in the critical
+// section together
+type Overlap struct {
+	A, B       int // process IDs, A < B
+	SymA, SymB rune
+	Start, End time.Duration
+}
+
+func (o Overlap) String() string {
+	return fmt.Sprintf("processes %d (%c) and %d (%c) both in %s from %.9fs to %.9fs",
+		o.A, o.SymA, o.B, o.SymB, CriticalSection, o.Start.Seconds(), o.End.Seconds())
+}
+
+// MutualExclusion reconstructs the state intervals of every process and
+// returns every pair of overlapping critical sections, ordered by start.
+// A process leaving the critical section at the instant another one
+// enters it does not count as an overlap.
+func MutualExclusion(f *trace.File) ([]Overlap, error) {
+	row := -1
+	for i, l := range f.Header.Rows {
+		if l == CriticalSection {
+			row = i
+		}
+	}
+	if row < 0 {
+		return nil, fmt.Errorf("trace has no %s row", CriticalSection)
+	}
+
+	var cs []trace.Interval
+	for _, iv := range f.Intervals() {
+		if iv.Position.Y == row && iv.End > iv.Start {
+			cs = append(cs, iv)
+		}
+	}
+	sort.SliceStable(cs, func(i, j int) bool { return cs[i].Start < cs[j].Start })
+
+	var overlaps []Overlap
+	for i, a := range cs {
+		for _, b := range cs[i+1:] {
+			if b.Start >= a.End {
+				break
+			}
+			if a.Id == b.Id {
+				continue
+			}
+			o := Overlap{A: a.Id, SymA: a.Symbol, B: b.Id, SymB: b.Symbol, Start: b.Start, End: min(a.End, b.End)}
+			if o.A > o.B {
+				o.A, o.B, o.SymA, o.SymB = o.B, o.A, o.SymB, o.SymA
+			}
+			overlaps = append(overlaps, o)
+		}
+	}
+	return overlaps, nil
+}
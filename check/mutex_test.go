@@ -0,0 +1,69 @@
+package check
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+const lista3Header = "-1 3 3 4 LOCAL_SECTION;ENTRY_PROTOCOL;CRITICAL_SECTION;EXIT_PROTOCOL;\n"
+
+func parse(t *testing.T, in string) *trace.File {
+	t.Helper()
+	f, err := trace.Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestMutualExclusion(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		wantN int
+		want  Overlap // the first one
+	}{
+		{"one after the other", "" +
+			"0.1 0 0 2 A\n0.2 0 0 3 A\n" +
+			"0.3 1 1 2 B\n0.4 1 1 3 B\n", 0, Overlap{}},
+		{"entering as the other leaves", "" +
+			"0.1 0 0 2 A\n0.2 0 0 3 A\n" +
+			"0.2 1 1 2 B\n0.3 1 1 3 B\n", 0, Overlap{}},
+		{"overlapping", "" +
+			"0.1 1 1 2 B\n0.3 1 1 3 B\n" +
+			"0.2 0 0 2 A\n0.4 0 0 3 A\n", 1,
+			Overlap{A: 0, SymA: 'A', B: 1, SymB: 'B', Start: 200 * time.Millisecond, End: 300 * time.Millisecond}},
+		{"three at once", "" +
+			"0.1 0 0 2 A\n0.5 0 0 3 A\n" +
+			"0.2 1 1 2 B\n0.5 1 1 3 B\n" +
+			"0.3 2 2 2 C\n0.5 2 2 3 C\n", 3,
+			Overlap{A: 0, SymA: 'A', B: 1, SymB: 'B', Start: 200 * time.Millisecond, End: 500 * time.Millisecond}},
+		{"still inside at the end", "" +
+			"0.1 0 0 2 A\n" +
+			"0.2 1 1 2 B\n0.3 1 1 3 B\n", 1,
+			Overlap{A: 0, SymA: 'A', B: 1, SymB: 'B', Start: 200 * time.Millisecond, End: 300 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MutualExclusion(parse(t, tt.in+lista3Header))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantN {
+				t.Fatalf("got %d overlaps %v, want %d", len(got), got, tt.wantN)
+			}
+			if tt.wantN > 0 && got[0] != tt.want {
+				t.Errorf("first overlap %v, want %v", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestMutualExclusionNeedsRows(t *testing.T) {
+	if _, err := MutualExclusion(parse(t, "0.1 0 0 2 A\n-1 1 5 5\n")); err == nil {
+		t.Error("a board trace passed the mutual exclusion check")
+	}
+}
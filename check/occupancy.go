@@ -0,0 +1,173 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Occupant is an entity holding a cell
+type Occupant struct {
+	Id     int
+	Kind   trace.Kind
+	Symbol rune
+}
+
+func (o Occupant) String() string {
+	return fmt.Sprintf("%s %d (%c)", o.Kind, o.Id, o.Symbol)
+}
+
+// Collision is a stretch of time two entities held the same cell
+type Collision struct {
+	Position   board.Position
+	A, B       Occupant // A.Id < B.Id
+	Start, End time.Duration
+}
+
+func (c Collision) String() string {
+	return fmt.Sprintf("%v and %v both on (%d, %d) from %.9fs to %.9fs",
+		c.A, c.B, c.Position.X, c.Position.Y, c.Start.Seconds(), c.End.Seconds())
+}
+
+// a cell holder and the time it arrived
+type holder struct {
+	Occupant
+	since time.Duration
+}
+
+type pair struct{ a, b int }
+
+// Occupancy replays a board trace in time order and returns every
+// stretch of time two entities held the same cell, ordered by start.
+//
+// A traveler or a wild tenant holds the cell of its last trace; an entity
+// at the hidden position (W, H) has left the board, a wild tenant whose
+// life ended or a go3 traveler that finished. A trap holds
+// its cell for the whole run and may share it with the one entity it
+// caught, which is released when the trap reports again.
+//
+// The traces sharing a time stamp are applied together, so an entity
+// leaving a cell at the instant another one enters it is no collision.
+// The programs take the time stamp after a move, so with the real clock
+// the arriving entity may be stamped a little before the one leaving;
+// collisions no longer than slack are ignored for that.
+func Occupancy(f *trace.File, slack time.Duration) ([]Collision, error) {
+	h := &f.Header
+	if len(h.Rows) > 0 {
+		return nil, errors.New("trace has row labels, it is not a board trace")
+	}
+	traces := append([]board.Trace(nil), f.Traces...)
+	sort.SliceStable(traces, func(i, j int) bool { return traces[i].TimeStamp < traces[j].TimeStamp })
+
+	cells := make(map[board.Position][]holder)
+	where := make(map[int]board.Position)
+	leave := func(id int) {
+		p, ok := where[id]
+		if !ok {
+			return
+		}
+		delete(where, id)
+		hs := cells[p]
+		for i := range hs {
+			if hs[i].Id == id {
+				cells[p] = append(hs[:i], hs[i+1:]...)
+				break
+			}
+		}
+	}
+
+	open := make(map[pair]*Collision)
+	var out []Collision
+	for i := 0; i < len(traces); {
+		now := traces[i].TimeStamp
+		touched := make(map[board.Position]bool)
+		for ; i < len(traces) && traces[i].TimeStamp == now; i++ {
+			t := traces[i]
+			o := Occupant{Id: t.Id, Kind: h.Kind(t.Id), Symbol: t.Symbol}
+			if o.Kind == trace.KindTrap {
+				if p, ok := where[t.Id]; ok && p == t.Position {
+					// the trap reports again: its catch is released
+					for _, c := range append([]holder(nil), cells[p]...) {
+						if c.Kind != trace.KindTrap && c.since < now {
+							leave(c.Id)
+						}
+					}
+					touched[p] = true
+					continue
+				}
+			}
+			if p, ok := where[t.Id]; ok {
+				touched[p] = true
+			}
+			leave(t.Id)
+			if h.Hidden(t.Position) {
+				continue
+			}
+			where[t.Id] = t.Position
+			cells[t.Position] = append(cells[t.Position], holder{o, now})
+			touched[t.Position] = true
+		}
+
+		// close the collisions that ended, then open the new ones
+		for k, c := range open {
+			if !touched[c.Position] || sharing(cells[c.Position], k) {
+				continue
+			}
+			c.End = now
+			out = append(out, *c)
+			delete(open, k)
+		}
+		for p := range touched {
+			hs := cells[p]
+			for x := range hs {
+				for y := x + 1; y < len(hs); y++ {
+					a, b := hs[x].Occupant, hs[y].Occupant
+					if (a.Kind == trace.KindTrap) != (b.Kind == trace.KindTrap) {
+						continue // a trap and its catch
+					}
+					if a.Id > b.Id {
+						a, b = b, a
+					}
+					k := pair{a.Id, b.Id}
+					if open[k] == nil {
+						open[k] = &Collision{Position: p, A: a, B: b, Start: now}
+					}
+				}
+			}
+		}
+	}
+	end := f.End()
+	for _, c := range open {
+		c.End = end
+		out = append(out, *c)
+	}
+
+	kept := out[:0]
+	for _, c := range out {
+		if slack == 0 || c.End-c.Start > slack {
+			kept = append(kept, c)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].Start != kept[j].Start {
+			return kept[i].Start < kept[j].Start
+		}
+		return kept[i].A.Id < kept[j].A.Id || kept[i].A.Id == kept[j].A.Id && kept[i].B.Id < kept[j].B.Id
+	})
+	return kept, nil
+}
+
+// sharing reports whether both entities of k still hold the cell
+func sharing(hs []holder, k pair) bool {
+	n := 0
+	for _, c := range hs {
+		if c.Id == k.a || c.Id == k.b {
+			n++
+		}
+	}
+	return n == 2
+}
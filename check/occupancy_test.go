@@ -0,0 +1,88 @@
+package check
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// travelers 0 and 1, wild tenant 2 and trap -1 on a 5x5 board
+const boardHeader = "-1 2 5 5 TRAPS=-1..-1;WILD=2..2;\n"
+
+func TestOccupancy(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		slack time.Duration
+		want  []Collision
+	}{
+		{"one after the other", "" +
+			"0.1 0 1 1 A\n0.2 0 2 1 A\n" +
+			"0.3 1 1 1 B\n", 0, nil},
+		{"swapping cells at once", "" +
+			"0.1 0 1 1 A\n0.3 0 2 1 A\n" +
+			"0.1 1 2 1 B\n0.3 1 1 1 B\n", 0, nil},
+		{"two travelers", "" +
+			"0.1 0 1 1 A\n0.3 0 2 1 A\n" +
+			"0.2 1 1 1 B\n", 0,
+			[]Collision{{Position: board.Position{X: 1, Y: 1},
+				A: Occupant{0, trace.KindTraveler, 'A'}, B: Occupant{1, trace.KindTraveler, 'B'},
+				Start: 200 * time.Millisecond, End: 300 * time.Millisecond}}},
+		{"within the slack", "" +
+			"0.1 0 1 1 A\n0.3 0 2 1 A\n" +
+			"0.2 1 1 1 B\n", 200 * time.Millisecond, nil},
+		{"a traveler holds its last cell", "" +
+			"0.1 0 1 1 A\n" +
+			"0.2 1 0 1 B\n0.4 1 1 1 B\n0.5 1 2 1 B\n", 0,
+			[]Collision{{Position: board.Position{X: 1, Y: 1},
+				A: Occupant{0, trace.KindTraveler, 'A'}, B: Occupant{1, trace.KindTraveler, 'B'},
+				Start: 400 * time.Millisecond, End: 500 * time.Millisecond}}},
+		{"a finished go3 traveler leaves", "" +
+			"0.1 0 1 1 A\n0.2 0 5 5 A\n" +
+			"0.3 1 1 1 B\n", 0, nil},
+		{"a wild tenant leaves for the hidden cell", "" +
+			"0.1 2 1 1 7\n0.2 2 5 5 7\n" +
+			"0.3 0 1 1 A\n", 0, nil},
+		{"the hidden cell is shared", "" +
+			"0.1 2 5 5 7\n" +
+			"0.1 0 1 1 A\n0.2 0 5 5 A\n", 0, nil},
+		{"a trap and its catch", "" +
+			"0 -1 3 3 #\n" +
+			"0.1 0 3 3 a\n", 0, nil},
+		{"the trap releases its catch", "" +
+			"0 -1 3 3 #\n" +
+			"0.1 0 3 3 a\n0.6 -1 3 3 #\n" +
+			"0.7 1 3 3 b\n", 0, nil},
+		{"two catches at once", "" +
+			"0 -1 3 3 #\n" +
+			"0.1 0 3 3 a\n0.6 -1 3 3 #\n" +
+			"0.2 1 3 3 b\n", 0,
+			[]Collision{{Position: board.Position{X: 3, Y: 3},
+				A: Occupant{0, trace.KindTraveler, 'a'}, B: Occupant{1, trace.KindTraveler, 'b'},
+				Start: 200 * time.Millisecond, End: 600 * time.Millisecond}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Occupancy(parse(t, tt.in+boardHeader), tt.slack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("collision %d is %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOccupancyNeedsBoard(t *testing.T) {
+	if _, err := Occupancy(parse(t, "0.1 0 0 2 A\n"+lista3Header), 0); err == nil {
+		t.Error("a lista3 trace passed the occupancy check")
+	}
+}
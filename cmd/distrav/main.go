@@ -0,0 +1,179 @@
+// Command distrav replays a trace printed by the travelers programs or the
+// lista3 mutual exclusion programs in the terminal. It replaces
+// lista2/distrav.bash and lista3/display.bash.
+//
+//	go run ./lista2/zad4go > out
+//	go run ./cmd/distrav out
+//
+// Keys: space play/pause, right or l step forward, left or h step back,
+// + and - speed, g and G first and last step, q quit.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+func main() {
+	frame := flag.Int("frame", -1, "print the board after this step and exit, no interaction")
+	speed := flag.Float64("speed", 10, "steps per second when playing")
+	plain := flag.Bool("plain", false, "no colours")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: distrav [flags] trace-file")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *speed <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	tr, err := trace.Parse(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+	tr.Sort()
+
+	r := newReplay(tr.Header, tr.Traces)
+	p := ansi
+	if *plain || !term.IsTerminal(int(os.Stdout.Fd())) {
+		p = nil
+	}
+	if *frame >= 0 || !term.IsTerminal(int(os.Stdin.Fd())) {
+		r.seek(max(*frame, 0))
+		fmt.Print(r.render(p, "", "\n"))
+		return
+	}
+	if err := play(r, p, *speed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// play runs the interactive viewer until q is pressed
+func play(r *replay, p palette, speed float64) error {
+	fd := int(os.Stdin.Fd())
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, old)
+	fmt.Print("\x1b[?25l") // hide the cursor
+	defer fmt.Print("\x1b[?25h")
+
+	keys := make(chan rune)
+	go readKeys(keys)
+
+	playing := false
+	ticker := time.NewTicker(time.Hour)
+	ticker.Stop()
+	setSpeed := func(s float64) {
+		speed = min(max(s, 0.25), 1000)
+		if playing {
+			ticker.Reset(time.Duration(float64(time.Second) / speed))
+		}
+	}
+	draw := func() {
+		state := "paused"
+		if playing {
+			state = "playing"
+		}
+		status := fmt.Sprintf("[%s %g steps/s]  space play/pause  ←/→ step  +/- speed  g/G start/end  q quit", state, speed)
+		fmt.Print("\x1b[H\x1b[2J" + r.render(p, status, "\r\n"))
+	}
+
+	draw()
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch k {
+			case 'q', 3: // q or ctrl-c
+				return nil
+			case ' ':
+				playing = !playing
+				if playing {
+					setSpeed(speed)
+				} else {
+					ticker.Stop()
+				}
+			case 'l', keyRight:
+				playing = false
+				ticker.Stop()
+				r.forward()
+			case 'h', keyLeft:
+				playing = false
+				ticker.Stop()
+				r.back()
+			case '+', '=':
+				setSpeed(speed * 2)
+			case '-':
+				setSpeed(speed / 2)
+			case 'g', keyHome:
+				r.seek(0)
+			case 'G', keyEnd:
+				r.seek(len(r.events))
+			}
+		case <-ticker.C:
+			if !r.forward() {
+				playing = false
+				ticker.Stop()
+			}
+		}
+		draw()
+	}
+}
+
+// Keys sent as escape sequences
+const (
+	keyRight rune = -1 - iota
+	keyLeft
+	keyHome
+	keyEnd
+)
+
+// readKeys decodes the raw terminal input into keys
+func readKeys(keys chan<- rune) {
+	defer close(keys)
+	in := bufio.NewReader(os.Stdin)
+	for {
+		c, _, err := in.ReadRune()
+		if err != nil {
+			return
+		}
+		if c != 0x1b {
+			keys <- c
+			continue
+		}
+		// ESC [ C and friends
+		if b, _ := in.ReadByte(); b != '[' && b != 'O' {
+			continue
+		}
+		switch b, _ := in.ReadByte(); b {
+		case 'C':
+			keys <- keyRight
+		case 'D':
+			keys <- keyLeft
+		case 'H':
+			keys <- keyHome
+		case 'F':
+			keys <- keyEnd
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// placement is where an entity stands; among entities on the same cell
+// the one with the latest event is on top, as in the original scripts
+type placement struct {
+	pos     board.Position
+	symbol  rune
+	arrived int // step of the entity's latest event
+}
+
+// replay applies the events one at a time and can undo them
+type replay struct {
+	h      trace.Header
+	events []board.Trace
+	step   int // number of events applied
+	at     map[int]placement
+	undo   []undoRecord
+}
+
+type undoRecord struct {
+	id      int
+	before  placement
+	present bool
+}
+
+func newReplay(h trace.Header, events []board.Trace) *replay {
+	return &replay{h: h, events: events, at: make(map[int]placement)}
+}
+
+func (r *replay) forward() bool {
+	if r.step == len(r.events) {
+		return false
+	}
+	e := r.events[r.step]
+	before, present := r.at[e.Id]
+	r.undo = append(r.undo, undoRecord{id: e.Id, before: before, present: present})
+	r.step++
+	r.at[e.Id] = placement{pos: e.Position, symbol: e.Symbol, arrived: r.step}
+	return true
+}
+
+func (r *replay) back() bool {
+	if r.step == 0 {
+		return false
+	}
+	u := r.undo[len(r.undo)-1]
+	r.undo = r.undo[:len(r.undo)-1]
+	r.step--
+	if u.present {
+		r.at[u.id] = u.before
+	} else {
+		delete(r.at, u.id)
+	}
+	return true
+}
+
+// seek moves to the given step, clamped to the trace
+func (r *replay) seek(step int) {
+	for r.step < step && r.forward() {
+	}
+	for r.step > step && r.back() {
+	}
+}
+
+// grid returns the symbol on top of every cell, 0 for an empty cell;
+// entities on the hidden position (W, H) or outside the board are not shown
+func (r *replay) grid() [][]rune {
+	g := make([][]rune, r.h.Height)
+	top := make([][]int, r.h.Height)
+	for y := range g {
+		g[y] = make([]rune, r.h.Width)
+		top[y] = make([]int, r.h.Width)
+	}
+	for _, p := range r.at {
+		x, y := p.pos.X, p.pos.Y
+		if x < 0 || x >= r.h.Width || y < 0 || y >= r.h.Height {
+			continue
+		}
+		if p.arrived > top[y][x] {
+			top[y][x] = p.arrived
+			g[y][x] = p.symbol
+		}
+	}
+	return g
+}
+
+// time is the timestamp of the last applied event
+func (r *replay) time() float64 {
+	if r.step == 0 {
+		return 0
+	}
+	return r.events[r.step-1].TimeStamp.Seconds()
+}
+
+// palette colours the symbols; nil prints them plain
+type palette map[string]string
+
+var ansi = palette{
+	"trap":     "\x1b[31m", // red
+	"wild":     "\x1b[33m", // yellow
+	"trapped":  "\x1b[35m", // magenta
+	"deadlock": "\x1b[1;36m",
+	"reset":    "\x1b[0m",
+}
+
+func (p palette) paint(sym rune) string {
+	kind := ""
+	switch {
+	case sym == '#':
+		kind = "trap"
+	case sym == '*':
+		kind = "trapped"
+	case sym >= '0' && sym <= '9':
+		kind = "wild"
+	case sym >= 'a' && sym <= 'z':
+		kind = "deadlock"
+	}
+	if p == nil || kind == "" {
+		return string(sym)
+	}
+	return p[kind] + string(sym) + p["reset"]
+}
+
+// render draws the current frame; nl ends the lines, "\r\n" in raw mode
+func (r *replay) render(p palette, status, nl string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "STEP = %d/%d  TIME = %.6f%s", r.step, len(r.events), r.time(), nl)
+	for y, row := range r.grid() {
+		for _, sym := range row {
+			b.WriteByte('.')
+			if sym == 0 {
+				b.WriteByte('.')
+			} else {
+				b.WriteString(p.paint(sym))
+			}
+		}
+		if y < len(r.h.Rows) {
+			b.WriteString(" <- " + r.h.Rows[y])
+		}
+		b.WriteString(nl)
+	}
+	if footer := r.footer(); footer != "" {
+		b.WriteString(footer + nl)
+	}
+	if len(r.h.Rows) == 0 {
+		b.WriteString("A-Z traveler  a-z deadlocked or trapped  0-9 wild tenant  * trapped tenant  # trap" + nl)
+	}
+	if status != "" {
+		b.WriteString(status + nl)
+	}
+	return b.String()
+}
+
+// footer lists the labels that do not name a row, like MAX_TICKET=...
+func (r *replay) footer() string {
+	var params []string
+	for key, value := range r.h.Params {
+		params = append(params, key+"="+value)
+	}
+	slices.Sort(params)
+	labels := append(slices.Clone(r.h.Extra), params...)
+	return strings.Join(labels, "  ")
+}
@@ -0,0 +1,75 @@
+// Command fairness measures how fairly a lista3 algorithm served its
+// processes: critical sections entered, waits in the entry protocol,
+// entries of the others during a wait, and Jain's index of the waits.
+//
+//	go run ./lista3/go/zad2 > out
+//	go run ./cmd/fairness out
+//	go run ./cmd/fairness -json out > fairness.json
+//
+// The programs print the same metrics in the footer of the trace, see
+// package fairness.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/TrollYuck/PW_INA_2025/fairness"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: fairness [flags] trace-file")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, flag.Arg(0), *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, "fairness:", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, input string, asJSON bool) error {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	f, err := trace.Parse(in)
+	in.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	r, err := fairness.Measure(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "id\tsymbol\tentries\twaits\tmin\tmean\tp50\tp95\tmax\tbypasses\tmax bypass\t")
+	for _, p := range r.Processes {
+		ws := p.Wait
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%.6f\t%.6f\t%.6f\t%.6f\t%.6f\t%d\t%d\t\n", p.Id, p.Symbol, p.Entries, ws.Count,
+			ws.Min.Seconds(), ws.Mean.Seconds(), ws.P50.Seconds(), ws.P95.Seconds(), ws.Max.Seconds(), p.Bypasses, p.MaxBypass)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\nwaits in seconds, Jain's index of the mean waits %.4f\n", r.Jain)
+	return err
+}
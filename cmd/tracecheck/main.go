@@ -0,0 +1,88 @@
+// Command tracecheck verifies the invariants of a trace and exits with
+// status 1 when one is broken.
+//
+//	go run ./lista3/go/zad2 > out
+//	go run ./cmd/tracecheck -check mutex out
+//
+// The mutex check reports every pair of lista3 processes that were in
+// the critical section at the same time, the occupancy check every pair
+// of travelers, wild tenants or traps that held the same cell.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/check"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+func main() {
+	which := flag.String("check", "mutex", "invariant to check: mutex or occupancy")
+	slack := flag.Duration("slack", 0, "occupancy: ignore collisions no longer than this, for real-clock traces")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: tracecheck [flags] trace-file")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	n, err := run(os.Stdout, flag.Arg(0), *which, *slack)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tracecheck:", err)
+		os.Exit(2)
+	}
+	if n > 0 {
+		os.Exit(1)
+	}
+}
+
+// run checks the trace in the file input and prints the violations; it
+// returns how many were found
+func run(w io.Writer, input, which string, slack time.Duration) (int, error) {
+	in, err := os.Open(input)
+	if err != nil {
+		return 0, err
+	}
+	f, err := trace.Parse(in)
+	in.Close()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", input, err)
+	}
+
+	var violations []fmt.Stringer
+	switch which {
+	case "mutex":
+		overlaps, err := check.MutualExclusion(f)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", input, err)
+		}
+		for _, o := range overlaps {
+			violations = append(violations, o)
+		}
+	case "occupancy":
+		collisions, err := check.Occupancy(f, slack)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", input, err)
+		}
+		for _, c := range collisions {
+			violations = append(violations, c)
+		}
+	default:
+		return 0, fmt.Errorf("unknown check %q", which)
+	}
+
+	for _, v := range violations {
+		fmt.Fprintf(w, "%s: %v\n", input, v)
+	}
+	if len(violations) == 0 {
+		fmt.Fprintf(w, "%s: %s ok\n", input, which)
+	}
+	return len(violations), nil
+}
@@ -0,0 +1,120 @@
+// Command traceexport converts a text trace into other formats.
+//
+//	go run ./lista3/go/zad2 > out
+//	go run ./cmd/traceexport -to chrome out > out.json
+//
+// The chrome format is the trace-event JSON of chrome://tracing and
+// https://ui.perfetto.dev, for the lista3 programs: one track per
+// process, one slice per state.
+//
+// The gif and apng formats animate the run of any list, a frame every
+// -interval of the time of the trace, and svg writes every frame to its
+// own file in the -o directory, for the reports:
+//
+//	go run ./cmd/traceexport -to gif -interval 50ms out > out.gif
+//	go run ./cmd/traceexport -to svg -o frames out
+//
+// See package render.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/TrollYuck/PW_INA_2025/render"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+func main() {
+	to := flag.String("to", "chrome", "output format: chrome, gif, apng or svg")
+	output := flag.String("o", "", "output file (default: standard output), the directory of the frames for svg")
+	var opt render.Options
+	flag.DurationVar(&opt.Interval, "interval", 0, "time of the trace between frames (default: a hundredth of the run)")
+	flag.Float64Var(&opt.Speed, "speed", 1, "playback speed of gif and apng, 2 is twice as fast as the run")
+	flag.IntVar(&opt.Cell, "cell", render.DefaultCell, "side of a cell in pixels")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: traceexport [flags] trace-file")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *to, *output, opt); err != nil {
+		fmt.Fprintln(os.Stderr, "traceexport:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, to, output string, opt render.Options) error {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	f, err := trace.Parse(in)
+	in.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	if to == "svg" {
+		return exportFrames(f, output, opt)
+	}
+	if output == "" {
+		return export(os.Stdout, f, to, filepath.Base(input), opt)
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := export(out, f, to, filepath.Base(input), opt); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func export(w io.Writer, f *trace.File, to, name string, opt render.Options) error {
+	switch to {
+	case "chrome":
+		return trace.WriteChrome(w, f, name)
+	case "gif":
+		return render.WriteGIF(w, f, opt)
+	case "apng":
+		return render.WriteAPNG(w, f, opt)
+	}
+	return fmt.Errorf("unknown output format %q", to)
+}
+
+// exportFrames writes every frame to dir/frame0000.svg and on
+func exportFrames(f *trace.File, dir string, opt render.Options) error {
+	if dir == "" {
+		return fmt.Errorf("svg needs the directory of the frames, -o")
+	}
+	frames, err := render.Frames(f, opt)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, fr := range frames {
+		out, err := os.Create(filepath.Join(dir, fmt.Sprintf("frame%04d.svg", i)))
+		if err != nil {
+			return err
+		}
+		if err := render.WriteSVG(out, &f.Header, fr, opt); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
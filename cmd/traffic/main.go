@@ -0,0 +1,105 @@
+// Command traffic reports how the cells of a board trace were used:
+// visits, occupancy time, requests refused because the cell was taken,
+// trap catches and travelers stuck in lowercase, as ASCII heatmaps, and
+// whether the visits are spread uniformly over the torus.
+//
+//	go run ./lista1/go1 > out
+//	go run ./cmd/traffic out
+//	go run ./cmd/traffic -metric refused -png refused.png -csv cells.csv out
+//
+// Only go2, zad2 and zad4 count the refused requests, see package traffic.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+	"github.com/TrollYuck/PW_INA_2025/traffic"
+)
+
+func main() {
+	metric := flag.String("metric", "", "heatmap to draw: visits, occupancy, refused, traps or stuck (default: all of them, visits for -png)")
+	csvFile := flag.String("csv", "", "also write every metric of every cell to this CSV file")
+	pngFile := flag.String("png", "", "also draw the heatmap to this PNG file")
+	cell := flag.Int("cell", 24, "side of a cell of the PNG heatmap in pixels")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: traffic [flags] trace-file")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, flag.Arg(0), *metric, *csvFile, *pngFile, *cell); err != nil {
+		fmt.Fprintln(os.Stderr, "traffic:", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, input, metric, csvFile, pngFile string, cell int) error {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	f, err := trace.Parse(in)
+	in.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	m, err := traffic.Analyze(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	metrics := traffic.Metrics
+	if metric != "" {
+		mt, err := traffic.MetricNamed(metric)
+		if err != nil {
+			return err
+		}
+		metrics = []traffic.Metric{mt}
+	}
+	for i, mt := range metrics {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if mt.Name == "refused" && !m.Contention {
+			fmt.Fprintln(w, "refused: not counted, the trace has no CONTENTION parameter")
+			continue
+		}
+		if err := traffic.WriteASCII(w, m, mt); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "\nuniformity of the visits: %v\n", m.Uniformity())
+
+	if csvFile != "" {
+		if err := create(csvFile, func(out io.Writer) error { return traffic.WriteCSV(out, m) }); err != nil {
+			return err
+		}
+	}
+	if pngFile != "" {
+		if err := create(pngFile, func(out io.Writer) error { return traffic.WritePNG(out, m, metrics[0], cell) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create writes the file name with write
+func create(name string, write func(out io.Writer) error) error {
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(out); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,132 @@
+// Package dashboard shows a run live in the browser. A Server receives
+// the trace of the run in JSON Lines, see trace.Writer, and serves a
+// self-contained page drawing it: the torus with its travelers, wild
+// tenants and traps, or for lista3 the processes as columns over the
+// rows of their states, with the critical section highlighted.
+//
+// The page follows the run over Server-Sent Events at /events; every
+// line of the trace is the data of one message, and a last message of
+// type "end" tells that the run is over. A page opened late receives the
+// whole trace so far first.
+package dashboard
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+//go:embed index.html
+var page []byte
+
+// Server is an io.Writer for the trace of a single run and the
+// http.Handler of its page
+type Server struct {
+	mu        sync.Mutex
+	lines     [][]byte      // complete lines of the trace so far
+	partial   []byte        // the line being written
+	finished  bool          // Finish was called
+	changed   chan struct{} // closed, and replaced, on every change
+	connected chan struct{} // closed when the first page connects
+	mux       *http.ServeMux
+}
+
+// New returns a server with an empty trace
+func New() *Server {
+	s := &Server{changed: make(chan struct{}), connected: make(chan struct{}), mux: http.NewServeMux()}
+	s.mux.HandleFunc("/", s.page)
+	s.mux.HandleFunc("/events", s.events)
+	return s
+}
+
+// Write adds to the trace, which is split into lines
+func (s *Server) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		s.lines = append(s.lines, bytes.Clone(s.partial[:i]))
+		s.partial = s.partial[i+1:]
+	}
+	s.notify()
+	return len(p), nil
+}
+
+// Finish tells the pages that the run is over
+func (s *Server) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished = true
+	s.notify()
+}
+
+// Connected is closed once a page follows the run
+func (s *Server) Connected() <-chan struct{} {
+	return s.connected
+}
+
+// notify wakes the pages up; s.mu must be held
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) page(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// events streams the trace, from its first line on
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	s.mu.Lock()
+	select {
+	case <-s.connected:
+	default:
+		close(s.connected)
+	}
+	s.mu.Unlock()
+
+	sent := 0
+	for {
+		s.mu.Lock()
+		lines, finished, changed := s.lines[sent:], s.finished, s.changed
+		s.mu.Unlock()
+		for _, l := range lines {
+			fmt.Fprintf(w, "data: %s\n\n", l)
+		}
+		sent += len(lines)
+		if finished {
+			fmt.Fprint(w, "event: end\ndata: {}\n\n")
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
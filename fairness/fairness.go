@@ -0,0 +1,206 @@
+// Package fairness measures how fairly a lista3 mutual-exclusion
+// algorithm serves its processes, from their state traces: how often
+// each entered the critical section, how long it waited in the entry
+// protocol, how many times the others went first meanwhile, and Jain's
+// fairness index of the waits across the processes.
+package fairness
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/check"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// EntryProtocol is the row label of the entry protocol in the lista3
+// traces, where a process waits for the critical section. A tournament
+// has a row per level instead, ENTRY_PROTOCOL_1 and up.
+const EntryProtocol = "ENTRY_PROTOCOL"
+
+// Report holds the metrics of every process and across them
+type Report struct {
+	Processes []Process `json:"processes"` // in ID order
+	// Jain is Jain's fairness index of the mean waits: 1 when every
+	// process waits as long on average, 1/n when a single one waits
+	Jain float64 `json:"jain"`
+}
+
+// Process holds the metrics of a process
+type Process struct {
+	Id       int    `json:"id"`
+	Symbol   string `json:"symbol"`
+	Entries  int    `json:"entries"`  // critical sections entered
+	Wait     Waits  `json:"wait"`     // in the entry protocol, the waits that ended
+	Bypasses int    `json:"bypasses"` // entries of the others while it waited
+	// MaxBypass is the most entries of the others during one wait, which
+	// a fair algorithm bounds and a starving one does not
+	MaxBypass int `json:"max_bypass"`
+}
+
+// Waits sums up the waits of a process, JSON gives them in seconds
+type Waits struct {
+	Count              int
+	Min, Mean, Max     time.Duration
+	P50, P90, P95, P99 time.Duration // percentiles, nearest rank
+}
+
+func (w Waits) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count int     `json:"count"`
+		Min   float64 `json:"min"`
+		Mean  float64 `json:"mean"`
+		Max   float64 `json:"max"`
+		P50   float64 `json:"p50"`
+		P90   float64 `json:"p90"`
+		P95   float64 `json:"p95"`
+		P99   float64 `json:"p99"`
+	}{w.Count, w.Min.Seconds(), w.Mean.Seconds(), w.Max.Seconds(),
+		w.P50.Seconds(), w.P90.Seconds(), w.P95.Seconds(), w.P99.Seconds()})
+}
+
+// Measure computes the report of a lista3 trace. A wait is the time in
+// the entry protocol rows in a row, a wait still going on when the trace
+// ends is left out.
+func Measure(f *trace.File) (*Report, error) {
+	entry, cs := make(map[int]bool), -1
+	for i, l := range f.Header.Rows {
+		switch {
+		case l == EntryProtocol || strings.HasPrefix(l, EntryProtocol+"_"):
+			entry[i] = true
+		case l == check.CriticalSection:
+			cs = i
+		}
+	}
+	if len(entry) == 0 || cs < 0 {
+		return nil, fmt.Errorf("trace has no %s and %s rows", EntryProtocol, check.CriticalSection)
+	}
+
+	type wait struct{ start, end time.Duration }
+	byId := make(map[int]*Process)
+	waits := make(map[int][]wait)
+	var entries []trace.Interval
+	intervals := f.Intervals() // by process, in time order
+	for i, iv := range intervals {
+		p, ok := byId[iv.Id]
+		if !ok {
+			p = &Process{Id: iv.Id, Symbol: string(iv.Symbol)}
+			byId[iv.Id] = p
+		}
+		last := i+1 == len(intervals) || intervals[i+1].Id != iv.Id
+		switch {
+		case entry[iv.Position.Y]:
+			if last {
+				break
+			}
+			ws := waits[iv.Id]
+			if i > 0 && intervals[i-1].Id == iv.Id && entry[intervals[i-1].Position.Y] {
+				ws[len(ws)-1].end = iv.End // up a level of a tournament
+			} else {
+				waits[iv.Id] = append(ws, wait{iv.Start, iv.End})
+			}
+		case iv.Position.Y == cs:
+			p.Entries++
+			entries = append(entries, iv)
+		}
+	}
+
+	r := &Report{}
+	for _, p := range byId {
+		var ds []time.Duration
+		for _, w := range waits[p.Id] {
+			ds = append(ds, w.end-w.start)
+			bypass := 0
+			for _, e := range entries {
+				if e.Id != p.Id && e.Start > w.start && e.Start < w.end {
+					bypass++
+				}
+			}
+			p.Bypasses += bypass
+			p.MaxBypass = max(p.MaxBypass, bypass)
+		}
+		p.Wait = summarize(ds)
+		r.Processes = append(r.Processes, *p)
+	}
+	sort.Slice(r.Processes, func(i, j int) bool { return r.Processes[i].Id < r.Processes[j].Id })
+	r.Jain = jain(r.Processes)
+	return r, nil
+}
+
+func summarize(ds []time.Duration) Waits {
+	w := Waits{Count: len(ds)}
+	if len(ds) == 0 {
+		return w
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	rank := func(p float64) time.Duration {
+		return ds[int(math.Ceil(p/100*float64(len(ds))))-1]
+	}
+	w.Min, w.Max, w.Mean = ds[0], ds[len(ds)-1], sum/time.Duration(len(ds))
+	w.P50, w.P90, w.P95, w.P99 = rank(50), rank(90), rank(95), rank(99)
+	return w
+}
+
+// jain is (sum x)^2 / (n sum x^2) over the mean waits of the processes
+// that waited
+func jain(ps []Process) float64 {
+	var sum, squares float64
+	n := 0
+	for _, p := range ps {
+		if p.Wait.Count == 0 {
+			continue
+		}
+		x := p.Wait.Mean.Seconds()
+		sum += x
+		squares += x * x
+		n++
+	}
+	if squares == 0 {
+		return 1
+	}
+	return sum * sum / (float64(n) * squares)
+}
+
+// Params returns the metrics as parameters of the footer of a trace:
+// JAIN, and a comma-separated value per process for CS_ENTRIES,
+// WAIT_MIN, WAIT_MEAN, WAIT_MAX and WAIT_P95 in seconds, and BYPASS_MAX
+func (r *Report) Params() map[string]string {
+	column := func(v func(p *Process) string) string {
+		fields := make([]string, len(r.Processes))
+		for i := range r.Processes {
+			fields[i] = v(&r.Processes[i])
+		}
+		return strings.Join(fields, ",")
+	}
+	seconds := func(d time.Duration) string { return strconv.FormatFloat(d.Seconds(), 'f', 6, 64) }
+	return map[string]string{
+		"JAIN":       strconv.FormatFloat(r.Jain, 'f', 4, 64),
+		"CS_ENTRIES": column(func(p *Process) string { return strconv.Itoa(p.Entries) }),
+		"WAIT_MIN":   column(func(p *Process) string { return seconds(p.Wait.Min) }),
+		"WAIT_MEAN":  column(func(p *Process) string { return seconds(p.Wait.Mean) }),
+		"WAIT_MAX":   column(func(p *Process) string { return seconds(p.Wait.Max) }),
+		"WAIT_P95":   column(func(p *Process) string { return seconds(p.Wait.P95) }),
+		"BYPASS_MAX": column(func(p *Process) string { return strconv.Itoa(p.MaxBypass) }),
+	}
+}
+
+// Footer measures the traces a lista3 program printed, with the given
+// row labels, and returns the parameters of its footer, none if the rows
+// lack the protocol states
+func Footer(rows []string, traces []board.Trace) map[string]string {
+	r, err := Measure(&trace.File{Header: trace.Header{Rows: rows}, Traces: traces})
+	if err != nil {
+		return make(map[string]string)
+	}
+	return r.Params()
+}
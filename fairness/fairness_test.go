@@ -0,0 +1,143 @@
+package fairness
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+const (
+	lockHeader       = "-1 2 2 4 LOCAL_SECTION;ENTRY_PROTOCOL;CRITICAL_SECTION;EXIT_PROTOCOL;\n"
+	tournamentHeader = "-1 2 2 5 LOCAL_SECTION;ENTRY_PROTOCOL_1;ENTRY_PROTOCOL_2;CRITICAL_SECTION;EXIT_PROTOCOL;\n"
+)
+
+func parse(t *testing.T, in string) *trace.File {
+	t.Helper()
+	f, err := trace.Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Process
+		jain float64
+	}{
+		{"a lock of two", lockHeader +
+			"0 0 0 0 A\n0.1 0 0 1 A\n0.2 0 0 2 A\n0.3 0 0 3 A\n0.4 0 0 0 A\n0.5 0 0 1 A\n0.9 0 0 2 A\n1.0 0 0 3 A\n1.1 0 0 0 A\n" +
+			"0 1 1 0 B\n0.15 1 1 1 B\n0.55 1 1 2 B\n0.6 1 1 3 B\n0.7 1 1 0 B\n",
+			[]Process{
+				// bypassed by B at 0.55 while waiting from 0.5 to 0.9
+				{Id: 0, Symbol: "A", Entries: 2, Bypasses: 1, MaxBypass: 1, Wait: Waits{Count: 2,
+					Min: ms(100), Mean: ms(250), Max: ms(400), P50: ms(100), P90: ms(400), P95: ms(400), P99: ms(400)}},
+				// bypassed by A at 0.2 while waiting from 0.15 to 0.55
+				{Id: 1, Symbol: "B", Entries: 1, Bypasses: 1, MaxBypass: 1, Wait: Waits{Count: 1,
+					Min: ms(400), Mean: ms(400), Max: ms(400), P50: ms(400), P90: ms(400), P95: ms(400), P99: ms(400)}},
+			},
+			// (0.25+0.4)^2 / 2(0.25^2+0.4^2)
+			0.4225 / 0.445},
+		{"the levels of a tournament are one wait", tournamentHeader +
+			"0 0 0 0 A\n0.1 0 0 1 A\n0.3 0 0 2 A\n0.6 0 0 3 A\n0.7 0 0 4 A\n0.8 0 0 0 A\n" +
+			"0 1 1 0 B\n0.2 1 1 1 B\n0.25 1 1 2 B\n0.4 1 1 3 B\n0.5 1 1 4 B\n0.55 1 1 0 B\n",
+			[]Process{
+				{Id: 0, Symbol: "A", Entries: 1, Bypasses: 1, MaxBypass: 1, Wait: Waits{Count: 1,
+					Min: ms(500), Mean: ms(500), Max: ms(500), P50: ms(500), P90: ms(500), P95: ms(500), P99: ms(500)}},
+				{Id: 1, Symbol: "B", Entries: 1, Wait: Waits{Count: 1,
+					Min: ms(200), Mean: ms(200), Max: ms(200), P50: ms(200), P90: ms(200), P95: ms(200), P99: ms(200)}},
+			},
+			0.49 / 0.58},
+		{"a wait cut by the end of the trace", lockHeader +
+			"0 0 0 0 A\n0.1 0 0 1 A\n" +
+			"0 1 1 1 B\n0.2 1 1 2 B\n0.3 1 1 3 B\n",
+			[]Process{
+				{Id: 0, Symbol: "A"},
+				{Id: 1, Symbol: "B", Entries: 1, Wait: Waits{Count: 1,
+					Min: ms(200), Mean: ms(200), Max: ms(200), P50: ms(200), P90: ms(200), P95: ms(200), P99: ms(200)}},
+			},
+			1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Measure(parse(t, tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(r.Processes, tt.want) {
+				t.Errorf("processes\n%+v\nwant\n%+v", r.Processes, tt.want)
+			}
+			if math.Abs(r.Jain-tt.jain) > 1e-9 {
+				t.Errorf("Jain's index %v, want %v", r.Jain, tt.jain)
+			}
+		})
+	}
+}
+
+func TestMeasureRows(t *testing.T) {
+	_, err := Measure(parse(t, "-1 1 1 2 LOCAL_SECTION;CRITICAL_SECTION;\n0 0 0 0 A\n"))
+	if err == nil {
+		t.Error("no error for a trace without an entry protocol")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []time.Duration
+		want Waits
+	}{
+		{"none", nil, Waits{}},
+		{"one", []time.Duration{ms(3)}, Waits{Count: 1,
+			Min: ms(3), Mean: ms(3), Max: ms(3), P50: ms(3), P90: ms(3), P95: ms(3), P99: ms(3)}},
+		{"ten, shuffled", []time.Duration{ms(7), ms(2), ms(10), ms(1), ms(5), ms(9), ms(3), ms(8), ms(4), ms(6)}, Waits{Count: 10,
+			Min: ms(1), Mean: 5500 * time.Microsecond, Max: ms(10), P50: ms(5), P90: ms(9), P95: ms(10), P99: ms(10)}},
+		{"a hundred", func() []time.Duration {
+			ds := make([]time.Duration, 100)
+			for i := range ds {
+				ds[i] = ms(100 - i)
+			}
+			return ds
+		}(), Waits{Count: 100,
+			Min: ms(1), Mean: 50500 * time.Microsecond, Max: ms(100), P50: ms(50), P90: ms(90), P95: ms(95), P99: ms(99)}},
+	}
+	for _, tt := range tests {
+		if got := summarize(tt.in); got != tt.want {
+			t.Errorf("%s: %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestJain(t *testing.T) {
+	waited := func(means ...time.Duration) []Process {
+		ps := make([]Process, len(means))
+		for i, m := range means {
+			ps[i] = Process{Id: i, Wait: Waits{Count: 1, Mean: m}}
+		}
+		return ps
+	}
+	tests := []struct {
+		name string
+		ps   []Process
+		want float64
+	}{
+		{"nobody waited", []Process{{Id: 0}, {Id: 1}}, 1},
+		{"the same waits", waited(ms(20), ms(20), ms(20)), 1},
+		{"a single one waits", waited(ms(20), 0, 0, 0), 0.25},
+		{"twice as long", waited(ms(10), ms(20)), 0.9},
+		{"the ones that never waited left out", append(waited(ms(10), ms(10)), Process{Id: 2}), 1},
+	}
+	for _, tt := range tests {
+		if got := jain(tt.ps); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/TrollYuck/PW_INA_2025
+
+go 1.22
+
+require (
+	golang.org/x/term v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.28.0 // indirect
@@ -0,0 +1,29 @@
+// Command go1 prints the trace of a run of the travelers simulation of
+// lista 1, see package travelers.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/lista1/go1/travelers"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := travelers.New()
+	m.Config.RegisterFlags(flag.CommandLine)
+	scenarioFile := flag.String("scenario", "", "JSON or YAML file describing the run")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	var err error
+	if m.Scenario, err = scenario.Setup(&m.Config, *scenarioFile, false); err == nil {
+		_, err = flags.Run(m)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,143 @@
+// Package travelers is the travelers simulation of lista 1: travelers
+// walking at random on the torus, any number of them on a cell.
+package travelers
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Config   board.Config
+	Scenario *scenario.Scenario // explicit setup applied to Config, may be nil
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Config: board.DefaultConfig()}
+}
+
+// world is the state of a single run, shared by its travelers
+type world struct {
+	cfg   board.Config
+	board *board.Board // 2D Board with torus topology
+	clock sched.Clock  // Timing, real or virtual
+	sim   *sim.Run
+}
+
+// TravelerTask represents a traveler task
+type TravelerTask struct {
+	*world
+	Id        int
+	Seed      int
+	Symbol    rune
+	Position  board.Position
+	Steps     int
+	Traces    []board.Trace
+	Generator *rand.Rand
+	Printer   *board.Printer
+	Spec      *scenario.Traveler // Explicit setup from a scenario file, may be nil
+}
+
+// Init initializes the traveler task
+func (t *TravelerTask) Init(id int, seed int, symbol rune) {
+	t.Id = id
+	t.Seed = seed
+	t.Symbol = t.Spec.SymbolOr(symbol)
+	t.Generator = rand.New(rand.NewSource(int64(seed)))
+	t.Position = t.Spec.StartOr(func() board.Position {
+		return t.board.RandomPosition(t.Generator)
+	})
+	t.Traces = make([]board.Trace, 0, t.cfg.MaxSteps+1)
+	t.StoreTrace()
+	t.Steps = t.Spec.StepsOr(func() int {
+		return t.cfg.MinSteps + t.Generator.Intn(t.cfg.MaxSteps-t.cfg.MinSteps)
+	})
+}
+
+// StoreTrace stores the current trace
+func (t *TravelerTask) StoreTrace() {
+	t.Traces = append(t.Traces, t.sim.Stamp(board.Trace{
+		Id:       t.Id,
+		Position: t.Position,
+		Symbol:   t.Symbol,
+		Event:    board.Move,
+	}))
+}
+
+// MakeStep makes a random step, or a step in the direction fixed by the scenario
+func (t *TravelerTask) MakeStep() {
+	if d, ok := t.Spec.Direction(); ok {
+		t.Position = t.board.Move(t.Position, d)
+		return
+	}
+	t.Position = t.board.RandomStep(t.Position, t.Generator)
+}
+
+// Start starts the traveler task
+func (t *TravelerTask) Start() {
+	for i := 0; i < t.Steps && !t.sim.Stopped(); i++ {
+		t.clock.Sleep(t.cfg.MinDelay + time.Duration(t.Generator.Int63n(int64(t.cfg.MaxDelay-t.cfg.MinDelay))))
+		t.MakeStep()
+		t.StoreTrace()
+	}
+	t.Printer.Report(board.TracesSequence{Id: t.Id, Traces: t.Traces})
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	cfg := m.Config
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := m.Scenario.Validate(&cfg, false); err != nil {
+		return err
+	}
+	w := &world{cfg: cfg, board: cfg.Board(), clock: r.Clock, sim: r}
+
+	header := trace.Header{
+		Travelers: cfg.NrOfTravelers,
+		Width:     w.board.Width,
+		Height:    w.board.Height,
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+	printer := r.NewPrinter(cfg.NrOfTravelers)
+	printer.Start()
+
+	travelers := make([]*TravelerTask, cfg.NrOfTravelers)
+	symbol := 'A'
+
+	// Initialize travelers, deriving the seed of each from the master seed
+	for i := 0; i < cfg.NrOfTravelers; i++ {
+		travelers[i] = &TravelerTask{
+			world:   w,
+			Printer: printer,
+			Spec:    m.Scenario.Traveler(i),
+		}
+		travelers[i].Init(i, int(r.Seed.Derive("traveler", i)), symbol)
+		symbol++
+	}
+
+	// Start travelers
+	for _, traveler := range travelers {
+		r.Clock.Go(traveler.Start)
+	}
+
+	// Wait for all travelers to finish
+	r.Clock.Wait()
+
+	// Stop the printer
+	printer.Stop()
+
+	// Print board parameters for display script
+	return r.End(nil)
+}
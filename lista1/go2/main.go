@@ -0,0 +1,33 @@
+// Command go2 prints the trace of a run of the travelers simulation of
+// lista 1 with a single traveler per cell, see package travelers2.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/lista1/go2/travelers2"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := travelers2.New()
+	m.Config.RegisterFlags(flag.CommandLine)
+	scenarioFile := flag.String("scenario", "", "JSON or YAML file describing the run")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	policy := board.RegisterPolicy(flag.CommandLine)
+	flag.Parse()
+	m.Policy = *policy
+	m.Config.NrOfWildSpawns, m.Config.NrOfTraps = 0, 0 // no wild tenants nor traps here
+	var err error
+	if m.Scenario, err = scenario.Setup(&m.Config, *scenarioFile, true); err == nil {
+		_, err = flags.Run(m)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,198 @@
+// Package travelers2 is the travelers simulation of lista 1 with a single
+// traveler per cell: a traveler that cannot get a cell in time asks its
+// policy what to do, and stops in lowercase when it gives up.
+package travelers2
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Config   board.Config
+	Scenario *scenario.Scenario // explicit setup applied to Config, may be nil
+	Policy   string             // what a blocked traveler does, see board.NewPolicy
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Config: board.DefaultConfig(), Policy: board.Policies[0]}
+}
+
+// world is the state of a single run, shared by its travelers
+type world struct {
+	cfg     board.Config
+	board   *board.Board
+	clock   sched.Clock
+	policy  *board.Resolver
+	printer *board.Printer
+	sim     *sim.Run
+}
+
+// traveler is the life of traveler id
+func (w *world) traveler(id int, sym rune, startCh <-chan struct{}, seed int64, spec *scenario.Traveler) {
+	// Per-traveler RNG
+	r := rand.New(rand.NewSource(seed))
+
+	// INIT phase
+	var pos board.Position
+	for {
+		if w.sim.Stopped() {
+			return // the cells no longer answer
+		}
+		pos = spec.StartOr(func() board.Position { return w.board.RandomPosition(r) })
+		if w.board.Cell(pos).Request().CanOccupy {
+			w.board.Cell(pos).Occupy(id)
+			break
+		}
+		w.clock.Sleep(1 * time.Millisecond)
+	}
+
+	steps := spec.StepsOr(func() int { return w.cfg.MinSteps + r.Intn(w.cfg.MaxSteps-w.cfg.MinSteps+1) })
+
+	// Collect traces
+	traces := make([]board.Trace, 0, steps+1)
+	record := func(sym rune, ev board.Event) {
+		traces = append(traces, w.sim.Stamp(board.Trace{
+			Id:       id,
+			Position: pos,
+			Symbol:   sym,
+			Event:    ev,
+		}))
+	}
+	record(sym, board.Move)
+
+	// WAIT for Start signal
+	<-startCh
+
+	// MOVEMENT phase
+	for step := 0; step < steps && !w.sim.Stopped(); step++ {
+		// Delay before move
+		d := w.cfg.MinDelay + time.Duration(r.Float64()*float64(w.cfg.MaxDelay-w.cfg.MinDelay))
+		w.clock.Sleep(d)
+
+		// Choose a direction
+		var newPos board.Position
+		if d, ok := spec.Direction(); ok {
+			newPos = w.board.Move(pos, d)
+		} else {
+			newPos = w.board.RandomStep(pos, r)
+		}
+
+		// Try to occupy new cell within MaxDelay, then ask the policy
+		startAttempt := w.clock.Now()
+		attempts := 0
+		moved := false
+		stuck := w.policy.Aborted(id) // wounded by an older traveler
+		// the cells stop serving once the run is stopped
+		for !stuck && !w.sim.Stopped() {
+			status := w.board.Cell(newPos).Request()
+			if status.CanOccupy {
+				// move
+				w.board.Cell(pos).Free()
+				w.board.Cell(newPos).Occupy(id)
+				pos = newPos
+				moved = true
+				break
+			}
+			if w.clock.Now()-startAttempt > w.cfg.MaxDelay {
+				attempts++
+				res := w.policy.Resolve(board.Conflict{Traveler: id, Holder: status.Id, Attempts: attempts, Rand: r})
+				switch res.Action {
+				case board.GiveUp:
+					stuck = true
+					continue
+				case board.Redirect:
+					newPos = w.board.Detour(pos, newPos, r)
+				}
+				w.clock.Sleep(res.Delay)
+				startAttempt = w.clock.Now()
+				continue
+			}
+			w.clock.Sleep(1 * time.Millisecond)
+		}
+
+		if stuck {
+			// stuck: lowercase symbol
+			sym = rune(int(sym) + 32)
+			record(sym, board.Deadlock)
+			w.policy.GaveUp()
+			break
+		}
+		if !moved {
+			break // stopped while waiting for the cell
+		}
+		w.policy.Moved()
+		record(sym, board.Move)
+	}
+
+	// Report to printer
+	w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	cfg := m.Config
+	cfg.NrOfWildSpawns, cfg.NrOfTraps = 0, 0 // no wild tenants nor traps here
+	if err := cfg.ValidateExclusive(); err != nil {
+		return err
+	}
+	if err := m.Scenario.Validate(&cfg, true); err != nil {
+		return err
+	}
+	policy, err := board.NewResolver(m.Policy, cfg.MaxDelay)
+	if err != nil {
+		return err
+	}
+
+	// Initialize board cells, they serve until the run ends or is stopped
+	w := &world{cfg: cfg, board: cfg.Board(), clock: r.Clock, policy: policy, sim: r}
+	w.board.ServeCells(r.Context)
+	defer w.board.Close()
+
+	// Start printer
+	header := trace.Header{
+		Travelers: cfg.NrOfTravelers,
+		Width:     w.board.Width,
+		Height:    w.board.Height,
+		Params:    map[string]string{"POLICY": policy.Name},
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+	w.printer = r.NewPrinter(cfg.NrOfTravelers)
+	w.printer.Start()
+
+	// Create start signal channel
+	startCh := make(chan struct{})
+
+	// Launch travelers (Init)
+	for i := 0; i < cfg.NrOfTravelers; i++ {
+		travelerSeed := r.Seed.Derive("traveler", i)
+		r.Clock.Go(func() {
+			spec := m.Scenario.Traveler(i)
+			w.traveler(i, spec.SymbolOr(rune('A'+i)), startCh, travelerSeed, spec)
+		})
+	}
+
+	// Signal all travelers to start
+	close(startCh)
+
+	// Wait for travelers and printer to finish
+	r.Clock.Wait()
+	w.printer.Stop()
+
+	// Print board parameters, the outcome of the policy and the contention at end
+	params := policy.Stats().Params()
+	if c := w.board.Contention(); len(c) > 0 {
+		params["CONTENTION"] = c.Param()
+	}
+	return r.End(params)
+}
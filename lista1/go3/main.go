@@ -0,0 +1,43 @@
+// Command go3 prints the trace of a run of the travelers simulation of
+// lista 1 with the travelers on the diagonal, see package travelers3.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/lista1/go3/travelers3"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := travelers3.New()
+	m.Config.RegisterFlags(flag.CommandLine)
+	scenarioFile := flag.String("scenario", "", "JSON or YAML file describing the run")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	journalFlags := sched.RegisterJournal(flag.CommandLine)
+	policy := board.RegisterPolicy(flag.CommandLine)
+	flag.BoolVar(&m.Avoid, "avoid", false,
+		"avoid deadlocks: a banker grants the moves along the known routes, only keeping the board safe")
+	flag.Parse()
+	m.Policy = *policy
+	m.Config.NrOfWildSpawns, m.Config.NrOfTraps = 0, 0 // no wild tenants nor traps here
+	var err error
+	if m.Scenario, err = scenario.Setup(&m.Config, *scenarioFile, true); err == nil {
+		m.Journal, err = journalFlags.Open(*flags.Clock)
+	}
+	if err == nil {
+		_, err = flags.Run(m)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := m.Journal.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
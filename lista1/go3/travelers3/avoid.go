@@ -0,0 +1,142 @@
+package travelers3
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+)
+
+// Verdict of the banker on a move
+type Verdict int
+
+const (
+	Granted  Verdict = iota
+	Occupied         // the cell is taken, wait for its traveler to leave
+	Unsafe           // the move could end in a deadlock, wait for others to move
+)
+
+// Banker replaces the cell locks when deadlocks are avoided rather than
+// detected. Every traveler declares its route, the cells it will step
+// on, and the banker grants a move only if the board stays safe: there
+// must be an order in which the travelers can walk the rest of their
+// routes one after the other, the later ones standing still and a
+// finished one leaving the board. From a safe board some traveler can
+// always move, so no cycle of waiting travelers ever forms.
+type Banker struct {
+	mu      sync.Mutex
+	at      map[int]board.Position   // travelers still on the board
+	route   map[int][]board.Position // cells still ahead of each of them
+	gates   map[int]sched.Mutex      // locked while the traveler waits
+	waiting []int
+	delayed int
+}
+
+// NewBanker returns a banker for travelers at the given cells with the
+// given routes, or an error if the board is not safe to begin with
+func NewBanker(clk sched.Clock, at map[int]board.Position, routes map[int][]board.Position) (*Banker, error) {
+	b := &Banker{at: at, route: routes, gates: make(map[int]sched.Mutex)}
+	for id := range at {
+		b.gates[id] = clk.NewMutex()
+		b.gates[id].Lock()
+	}
+	if !b.safe() {
+		return nil, errors.New("no order lets every traveler walk its route from the starting cells")
+	}
+	return b, nil
+}
+
+// Move asks to move traveler id to the next cell of its route, which
+// must be to; unless granted, the traveler must Await a change and ask
+// again
+func (b *Banker) Move(id int, to board.Position) Verdict {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for other, p := range b.at {
+		if p == to && other != id {
+			b.waiting = append(b.waiting, id)
+			return Occupied
+		}
+	}
+	from, route := b.at[id], b.route[id]
+	b.at[id], b.route[id] = to, route[1:]
+	if !b.safe() {
+		b.at[id], b.route[id] = from, route
+		b.waiting = append(b.waiting, id)
+		b.delayed++
+		return Unsafe
+	}
+	b.wake()
+	return Granted
+}
+
+// Leave takes a traveler that finished its route off the board
+func (b *Banker) Leave(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.at, id)
+	delete(b.route, id)
+	b.wake()
+}
+
+// Await blocks until the board changed after the last refused Move
+func (b *Banker) Await(id int) {
+	b.mu.Lock()
+	gate := b.gates[id]
+	b.mu.Unlock()
+	gate.Lock()
+}
+
+// Delayed returns the number of moves refused as unsafe
+func (b *Banker) Delayed() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.delayed
+}
+
+// wake lets every waiting traveler ask again; b.mu must be held
+func (b *Banker) wake() {
+	for _, id := range b.waiting {
+		b.gates[id].Unlock()
+	}
+	b.waiting = b.waiting[:0]
+}
+
+// safe looks for an order in which every traveler can finish; b.mu must
+// be held
+func (b *Banker) safe() bool {
+	ids := make([]int, 0, len(b.at))
+	for id := range b.at {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	finished := make(map[int]bool, len(ids))
+	for progress := true; progress; {
+		progress = false
+		for _, id := range ids {
+			if !finished[id] && b.clear(id, finished) {
+				finished[id] = true
+				progress = true
+			}
+		}
+	}
+	return len(finished) == len(ids)
+}
+
+// clear reports whether no unfinished traveler stands on the route of id
+func (b *Banker) clear(id int, finished map[int]bool) bool {
+	for other, p := range b.at {
+		if other == id || finished[other] {
+			continue
+		}
+		for _, q := range b.route[id] {
+			if p == q {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,411 @@
+// Package travelers3 is the travelers simulation of lista 1 with the
+// travelers starting on the diagonal and walking in a fixed direction,
+// each cell guarded by a lock. The travelers keep a wait-for graph of
+// the locks to detect deadlocks, or let a banker avoid them.
+package travelers3
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Config   board.Config
+	Scenario *scenario.Scenario // explicit setup applied to Config, may be nil
+	Policy   string             // what a traveler closing a cycle does, see board.NewPolicy
+	Journal  *sched.Journal     // lock grants and timeouts recorded or replayed, nil for a free run
+
+	// Avoid deadlocks: a banker grants the moves along the known routes
+	// instead of the cell locks, only keeping the board safe
+	Avoid bool
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Config: board.DefaultConfig(), Policy: board.Policies[0]}
+}
+
+// world is the state of a single run, shared by its travelers
+type world struct {
+	cfg       board.Config
+	cellLocks [][]sched.Mutex
+	board     *board.Board // 2D Board with torus topology
+	clock     sched.Clock  // Timing, real or virtual
+	journal   *sched.Journal
+	waits     *WaitFor        // Who holds and who waits for each cell
+	policy    *board.Resolver // What a traveler closing a cycle does
+	avoid     *Banker         // Grants the moves in the deadlock-avoiding mode, nil otherwise
+	sim       *sim.Run
+}
+
+// TravelerTask represents a traveler task
+type TravelerTask struct {
+	*world
+	Id        int
+	Seed      int
+	Symbol    rune
+	Position  board.Position
+	Steps     int
+	Traces    []board.Trace
+	Generator *rand.Rand
+	Printer   *board.Printer
+	Direction func(board.Position) board.Position // Fixed movement direction
+	Random    bool                                // Direction is a random step, the route is not known
+	Spec      *scenario.Traveler                  // Explicit setup from a scenario file, may be nil
+}
+
+// Init initializes the traveler task
+func (t *TravelerTask) Init(id int, seed int, symbol rune) {
+	t.Id = id
+	t.Seed = seed
+	t.Symbol = t.Spec.SymbolOr(symbol)
+	t.Generator = rand.New(rand.NewSource(int64(seed)))
+	t.Position = t.Spec.StartOr(func() board.Position {
+		return board.Position{X: id, Y: id} // Start on the diagonal
+	})
+	t.Traces = make([]board.Trace, 0, 3*t.cfg.MaxSteps+2) // a move and up to two waits per step, and leaving
+	t.StoreTrace(board.Move)
+	t.Steps = t.Spec.StepsOr(func() int {
+		return t.cfg.MinSteps + t.Generator.Intn(t.cfg.MaxSteps-t.cfg.MinSteps)
+	})
+
+	// Set fixed movement direction
+	if t.Spec.Fixed() {
+		// Direction chosen by the scenario
+		if d, ok := t.Spec.Direction(); ok {
+			t.Direction = func(p board.Position) board.Position { return t.board.Move(p, d) }
+		} else {
+			t.Direction = func(p board.Position) board.Position { return t.board.RandomStep(p, t.Generator) }
+			t.Random = true
+		}
+	} else if id%2 == 0 {
+		// Even ID: random vertical direction
+		if t.Generator.Intn(2) == 0 {
+			t.Direction = t.board.MoveUp
+		} else {
+			t.Direction = t.board.MoveDown
+		}
+	} else {
+		// Odd ID: random horizontal direction
+		if t.Generator.Intn(2) == 0 {
+			t.Direction = t.board.MoveLeft
+		} else {
+			t.Direction = t.board.MoveRight
+		}
+	}
+}
+
+// StoreTrace stores the current trace
+func (t *TravelerTask) StoreTrace(ev board.Event) {
+	if len(t.Traces) >= cap(t.Traces) {
+		// Prevent out-of-bounds access by stopping trace storage
+		fmt.Fprintf(os.Stderr, "Warning: Trace array full for traveler %d\n", t.Id)
+		return
+	}
+
+	t.Traces = append(t.Traces, t.sim.Stamp(board.Trace{
+		Id:       t.Id,
+		Position: t.Position,
+		Symbol:   t.Symbol,
+		Event:    ev,
+	}))
+}
+
+// wait records that the traveler waits for the cell at p, unless that
+// closes a cycle of waiting travelers, which it returns
+func (t *TravelerTask) wait(p board.Position) (cycle []int) {
+	t.journal.Do(t.Id, fmt.Sprintf("wait %d %d", p.X, p.Y), func() { cycle = t.waits.Wait(t.Id, p) })
+	return cycle
+}
+
+// cancel records that the traveler no longer waits for the cell at p
+func (t *TravelerTask) cancel(p board.Position) {
+	t.journal.Do(t.Id, fmt.Sprintf("cancel %d %d", p.X, p.Y), func() { t.waits.Cancel(t.Id) })
+}
+
+// aborted reports whether an older traveler wounded this one, or the
+// policy chose it to die and break a cycle
+func (t *TravelerTask) aborted() (wounded bool) {
+	t.journal.Do(t.Id, "aborted", func() { wounded = t.policy.Aborted(t.Id) })
+	return wounded
+}
+
+// resolve asks the policy what to do instead of closing cycle
+func (t *TravelerTask) resolve(cycle []int, attempts int) (res board.Resolution) {
+	c := board.Conflict{Traveler: t.Id, Holder: -1, Attempts: attempts, Rand: t.Generator, Cycle: cycle}
+	if len(cycle) > 1 {
+		c.Holder = cycle[1] // the traveler it would wait for
+	}
+	t.journal.Do(t.Id, "resolve", func() { res = t.policy.Resolve(c) })
+	return res
+}
+
+// hold records that the traveler got the cell at p
+func (t *TravelerTask) hold(p board.Position) {
+	t.journal.Do(t.Id, fmt.Sprintf("hold %d %d", p.X, p.Y), func() { t.waits.Hold(t.Id, p) })
+}
+
+// lock waits at most MaxDelay for the cell at p
+func (t *TravelerTask) lock(p board.Position) bool {
+	m := t.cellLocks[p.X][p.Y]
+	return t.journal.Decide(t.Id, fmt.Sprintf("lock %d %d", p.X, p.Y), func() bool {
+		return m.TryLockFor(t.cfg.MaxDelay)
+	}, func(granted bool) {
+		if granted {
+			m.Lock()
+		}
+	})
+}
+
+// unlock releases the cell at p
+func (t *TravelerTask) unlock(p board.Position) {
+	t.journal.Do(t.Id, fmt.Sprintf("unlock %d %d", p.X, p.Y), func() {
+		t.waits.Release(t.Id, p)
+		t.cellLocks[p.X][p.Y].Unlock()
+	})
+}
+
+// Route returns the cells the traveler will step on
+func (t *TravelerTask) Route() []board.Position {
+	route := make([]board.Position, 0, t.Steps+1)
+	route = append(route, t.Position)
+	for i := 0; i < t.Steps; i++ {
+		route = append(route, t.Direction(route[i]))
+	}
+	return route[1:]
+}
+
+// avoidStep makes a step granted by the banker, waiting for a slow
+// neighbour or for a move that keeps the board safe
+func (t *TravelerTask) avoidStep() {
+	newPos := t.Direction(t.Position)
+	marked := map[Verdict]bool{}
+	for {
+		var v Verdict
+		t.journal.Do(t.Id, fmt.Sprintf("move %d %d", newPos.X, newPos.Y), func() { v = t.avoid.Move(t.Id, newPos) })
+		if v == Granted {
+			break
+		}
+		// mark the first wait of each kind
+		if !marked[v] {
+			marked[v] = true
+			if v == Unsafe {
+				t.StoreTrace(board.Delayed)
+			} else {
+				t.StoreTrace(board.Blocked)
+			}
+		}
+		t.avoid.Await(t.Id)
+	}
+	t.Position = newPos
+	t.StoreTrace(board.Move)
+	t.policy.Moved()
+}
+
+// MakeStep makes a step in the fixed direction
+func (t *TravelerTask) MakeStep() bool {
+	if t.avoid != nil {
+		t.avoidStep()
+		return true
+	}
+	if t.aborted() {
+		return t.giveUp()
+	}
+	newPos := t.Direction(t.Position)
+
+	var last []int // the cycle of the previous attempt
+	for attempts := 1; ; attempts++ {
+		cycle := t.wait(newPos)
+		if cycle == nil {
+			break
+		}
+		// Waiting would close a cycle: deadlock detected, ask the policy.
+		// Retrying against the cycle still there is the same deadlock.
+		if !slices.Equal(cycle, last) {
+			t.waits.Record(cycle)
+			last = cycle
+		}
+		res := t.resolve(cycle, attempts)
+		switch res.Action {
+		case board.GiveUp:
+			return t.giveUp()
+		case board.Redirect:
+			newPos = t.board.Detour(t.Position, newPos, t.Generator)
+		}
+		t.clock.Sleep(res.Delay)
+	}
+	for n := 0; !t.lock(newPos); n++ {
+		// No cycle, only a slow neighbour: keep waiting, unless aborted
+		if t.aborted() {
+			t.cancel(newPos)
+			return t.giveUp()
+		}
+		if n == 0 {
+			t.StoreTrace(board.Blocked)
+		}
+	}
+	t.hold(newPos)
+	t.unlock(t.Position)
+	t.Position = newPos
+	t.StoreTrace(board.Move)
+	t.policy.Moved()
+	return true
+}
+
+// giveUp stops the traveler in lowercase
+func (t *TravelerTask) giveUp() bool {
+	t.Symbol = rune(t.Symbol + 32) // Convert symbol to lowercase
+	t.StoreTrace(board.Deadlock)   // Store the final trace
+	t.policy.GaveUp()
+	return false
+}
+
+// Start starts the traveler task
+func (t *TravelerTask) Start() {
+	for i := 0; i < t.Steps && !t.sim.Stopped(); i++ {
+		t.clock.Sleep(t.cfg.MinDelay + time.Duration(t.Generator.Int63n(int64(t.cfg.MaxDelay-t.cfg.MinDelay))))
+		if !t.MakeStep() {
+			// Traveler encountered a deadlock
+			break
+		}
+	}
+	// Leave the board, stamped before another traveler can take the cell
+	final := t.Position
+	t.Position = t.board.Hidden()
+	t.StoreTrace(board.Finished)
+	t.Printer.Report(board.TracesSequence{Id: t.Id, Traces: t.Traces})
+	if t.avoid != nil {
+		t.journal.Do(t.Id, "leave", func() { t.avoid.Leave(t.Id) })
+		return
+	}
+	t.unlock(final) // Unlock the final position
+}
+
+// checkStarts makes sure no two travelers start on the same cell, since
+// each of them locks its starting cell
+func checkStarts(cfg *board.Config, sc *scenario.Scenario) error {
+	taken := make(map[board.Position]int)
+	for i := 0; i < cfg.NrOfTravelers; i++ {
+		pos := sc.Traveler(i).StartOr(func() board.Position {
+			return board.Position{X: i, Y: i}
+		})
+		if pos.X >= cfg.BoardWidth || pos.Y >= cfg.BoardHeight {
+			return fmt.Errorf("traveler %d does not fit on the diagonal of a %dx%d board",
+				i, cfg.BoardWidth, cfg.BoardHeight)
+		}
+		if other, ok := taken[pos]; ok {
+			return fmt.Errorf("travelers %d and %d both start at (%d,%d)", other, i, pos.X, pos.Y)
+		}
+		taken[pos] = i
+	}
+	return nil
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	cfg := m.Config
+	cfg.NrOfWildSpawns, cfg.NrOfTraps = 0, 0 // no wild tenants nor traps here
+	err := cfg.ValidateExclusive()
+	if err == nil {
+		err = m.Scenario.Validate(&cfg, true)
+	}
+	if err == nil {
+		err = checkStarts(&cfg, m.Scenario)
+	}
+	if err != nil {
+		return err
+	}
+	w := &world{cfg: cfg, board: cfg.Board(), clock: r.Clock, journal: m.Journal, sim: r}
+	if w.policy, err = board.NewResolver(m.Policy, cfg.MaxDelay); err != nil {
+		return err
+	}
+
+	// Initialize the mutex grid and the wait-for graph
+	w.waits = NewWaitFor()
+	w.cellLocks = make([][]sched.Mutex, cfg.BoardWidth)
+	for i := range w.cellLocks {
+		w.cellLocks[i] = make([]sched.Mutex, cfg.BoardHeight)
+		for j := range w.cellLocks[i] {
+			w.cellLocks[i][j] = r.Clock.NewMutex()
+		}
+	}
+
+	travelers := make([]*TravelerTask, cfg.NrOfTravelers)
+	symbol := 'A'
+
+	// Initialize travelers, deriving the seed of each from the master seed
+	for i := 0; i < cfg.NrOfTravelers; i++ {
+		travelers[i] = &TravelerTask{
+			world: w,
+			Spec:  m.Scenario.Traveler(i),
+		}
+		travelers[i].Init(i, int(r.Seed.Derive("traveler", i)), symbol)
+		symbol++
+		w.cellLocks[travelers[i].Position.X][travelers[i].Position.Y].Lock() // Lock initial position
+		w.waits.Hold(i, travelers[i].Position)
+	}
+
+	// In the avoiding mode the routes must be known in advance
+	if m.Avoid {
+		at := make(map[int]board.Position)
+		routes := make(map[int][]board.Position)
+		for _, t := range travelers {
+			if t.Random {
+				return fmt.Errorf("avoiding deadlocks needs fixed directions, traveler %d steps at random", t.Id)
+			}
+			at[t.Id], routes[t.Id] = t.Position, t.Route()
+		}
+		if w.avoid, err = NewBanker(r.Clock, at, routes); err != nil {
+			return err
+		}
+	}
+
+	header := trace.Header{
+		Travelers: cfg.NrOfTravelers,
+		Width:     w.board.Width,
+		Height:    w.board.Height,
+		Params:    map[string]string{"POLICY": w.policy.Name},
+	}
+	if w.avoid != nil {
+		header.Params["AVOID"] = "banker"
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+	printer := r.NewPrinter(cfg.NrOfTravelers)
+
+	printer.Start()
+
+	// Start travelers
+	for _, traveler := range travelers {
+		traveler.Printer = printer
+		r.Clock.Go(traveler.Start)
+	}
+
+	// Wait for all travelers to finish
+	r.Clock.Wait()
+
+	printer.Stop()
+
+	// the outcome of the policy and the deadlocks found, each cycle
+	// starting with the traveler that closed it
+	params := w.policy.Stats().Params()
+	if w.avoid != nil {
+		params["DELAYED"] = strconv.Itoa(w.avoid.Delayed())
+	}
+	if cycles := w.waits.Cycles(); len(cycles) > 0 {
+		params["DEADLOCKS"] = FormatCycles(cycles)
+	}
+	return r.End(params)
+}
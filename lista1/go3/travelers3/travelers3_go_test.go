@@ -0,0 +1,90 @@
+package travelers3_test
+
+import (
+	"context"
+	"testing"
+	"unicode"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/check"
+	"github.com/TrollYuck/PW_INA_2025/lista1/go3/travelers3"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/seed"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+// crowded is go3 with 5 travelers starting on the diagonal of a 5x5 board,
+// each in its fixed direction and with many steps, so they keep crossing
+// each other's routes; the scenario only gives the steps
+func crowded(avoid bool) *travelers3.Model {
+	m := travelers3.New()
+	m.Config.BoardWidth, m.Config.BoardHeight = 5, 5
+	m.Config.NrOfTravelers, m.Config.MinSteps = 5, 50
+	m.Scenario = &scenario.Scenario{}
+	for i := range m.Config.NrOfTravelers {
+		steps := 50 + 10*i
+		m.Scenario.Travelers = append(m.Scenario.Travelers, scenario.Traveler{Steps: &steps})
+	}
+	m.Avoid = avoid
+	return m
+}
+
+// TestSingleOccupancy runs go3 under the virtual clock: the cell locks
+// keep every traveler on a cell of its own, including the ones that
+// finished and freed theirs
+func TestSingleOccupancy(t *testing.T) {
+	for _, avoid := range []bool{false, true} {
+		for s := seed.Master(1); s <= 5; s++ {
+			sm := sim.Simulation{Model: crowded(avoid), Seed: s, Clock: sched.KindVirtual}
+			res, err := sm.Run(context.Background())
+			if err != nil {
+				t.Fatalf("seed %v, avoid %v: %v", s, avoid, err)
+			}
+			collisions, err := check.Occupancy(res.File(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range collisions {
+				t.Errorf("seed %v, avoid %v: %v", s, avoid, c)
+			}
+		}
+	}
+}
+
+// TestAvoidCompletes checks that with the banker every traveler makes all
+// of its steps, however often it is held back
+func TestAvoidCompletes(t *testing.T) {
+	delayed := 0
+	for s := seed.Master(1); s <= 5; s++ {
+		m := crowded(true)
+		sm := sim.Simulation{Model: m, Seed: s, Clock: sched.KindVirtual}
+		res, err := sm.Run(context.Background())
+		if err != nil {
+			t.Fatalf("seed %v: %v", s, err)
+		}
+		if len(res.Deadlocked) > 0 {
+			t.Errorf("seed %v: travelers %v deadlocked", s, res.Deadlocked)
+		}
+		moves := make([]int, m.Config.NrOfTravelers)
+		for _, tr := range res.Traces {
+			switch {
+			case unicode.IsLower(tr.Symbol):
+				t.Errorf("seed %v: traveler %d turned lowercase at %v", s, tr.Id, tr.TimeStamp)
+			case tr.Event == board.Move:
+				moves[tr.Id]++
+			case tr.Event == board.Delayed:
+				delayed++
+			}
+		}
+		for i, n := range moves {
+			// the first move is the start
+			if want := *m.Scenario.Travelers[i].Steps; n-1 != want {
+				t.Errorf("seed %v: traveler %d made %d steps, want %d", s, i, n-1, want)
+			}
+		}
+	}
+	if delayed == 0 {
+		t.Error("the banker never delayed a traveler")
+	}
+}
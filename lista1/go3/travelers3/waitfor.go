@@ -0,0 +1,114 @@
+package travelers3
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+// WaitFor is the wait-for graph of the travelers: the traveler holding
+// each locked cell and the cell each blocked traveler waits for. Every
+// traveler holds one cell and waits for at most one, so a traveler waits
+// for the holder of its cell and a deadlock is a cycle of such edges.
+type WaitFor struct {
+	mu      sync.Mutex
+	holder  map[board.Position]int
+	waiting map[int]board.Position
+	cycles  [][]int
+}
+
+// NewWaitFor returns an empty graph
+func NewWaitFor() *WaitFor {
+	return &WaitFor{holder: make(map[board.Position]int), waiting: make(map[int]board.Position)}
+}
+
+// Hold records that traveler id got the lock of the cell at p
+func (g *WaitFor) Hold(id int, p board.Position) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.holder[p] = id
+	delete(g.waiting, id)
+}
+
+// Release records that traveler id unlocks the cell at p; it must be
+// called before the lock is released, so that the graph never shows a
+// holder that already left
+func (g *WaitFor) Release(id int, p board.Position) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if h, ok := g.holder[p]; ok && h == id {
+		delete(g.holder, p)
+	}
+}
+
+// Wait records that traveler id is about to wait for the cell at p.
+// If the wait would close a cycle it records nothing and returns the
+// travelers of the cycle, starting with id, each waiting for the next
+// and the last for id. A chain of waits that ends at a traveler that is
+// not waiting, a slow neighbour, is no cycle.
+//
+// A cycle can only be closed by a new wait: a traveler is not waiting
+// when it gets a lock, so checking here finds every deadlock. A traveler
+// that backs off and tries again finds the same cycle again, the caller
+// records each deadlock once with Record.
+func (g *WaitFor) Wait(id int, p board.Position) []int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cycle := []int{id}
+	for at := p; ; {
+		h, ok := g.holder[at]
+		if !ok {
+			break
+		}
+		if h == id {
+			return cycle
+		}
+		cycle = append(cycle, h)
+		if at, ok = g.waiting[h]; !ok {
+			break
+		}
+	}
+	g.waiting[id] = p
+	return nil
+}
+
+// Record adds a deadlock to the cycles found
+func (g *WaitFor) Record(cycle []int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cycles = append(g.cycles, cycle)
+}
+
+// Cancel records that traveler id no longer waits
+func (g *WaitFor) Cancel(id int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.waiting, id)
+}
+
+// Cycles returns the cycles found so far, in the order they were found
+func (g *WaitFor) Cycles() [][]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.cycles
+}
+
+// FormatCycles writes cycles as "0>2>4,1>3": the traveler that closed
+// the cycle first, then the one it waited for and so on
+func FormatCycles(cycles [][]int) string {
+	var sb strings.Builder
+	for i, c := range cycles {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		for j, id := range c {
+			if j > 0 {
+				sb.WriteByte('>')
+			}
+			sb.WriteString(strconv.Itoa(id))
+		}
+	}
+	return sb.String()
+}
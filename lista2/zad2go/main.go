@@ -0,0 +1,34 @@
+// Command zad2go prints the trace of a run of the wild tenants simulation
+// of lista 2, see package wild.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/lista2/zad2go/wild"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := wild.New()
+	m.Config.RegisterFlags(flag.CommandLine)
+	m.Config.RegisterWildFlags(flag.CommandLine)
+	scenarioFile := flag.String("scenario", "", "JSON or YAML file describing the run")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	policy := board.RegisterPolicy(flag.CommandLine)
+	flag.Parse()
+	m.Policy = *policy
+	m.Config.NrOfTraps = 0 // no traps here
+	var err error
+	if m.Scenario, err = scenario.Setup(&m.Config, *scenarioFile, true); err == nil {
+		_, err = flags.Run(m)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,276 @@
+// Package wild is the travelers simulation of lista 2 with wild tenants:
+// the cells are served by goroutines, and a tenant standing in the way of
+// a traveler relocates to a free neighbour if it can.
+package wild
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Config   board.Config
+	Scenario *scenario.Scenario // explicit setup applied to Config, may be nil
+	Policy   string             // what a blocked traveler does, see board.NewPolicy
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Config: board.DefaultConfig(), Policy: board.Policies[0]}
+}
+
+// world is the state of a single run, shared by its travelers and tenants
+type world struct {
+	cfg     board.Config
+	board   *board.Board
+	clock   sched.Clock
+	policy  *board.Resolver
+	printer *board.Printer
+	sim     *sim.Run
+}
+
+// Traces:
+// Id < NrOfTravelers => normal; Id >= NrOfTravelers => wild
+// Symbol '0'-'9' for wild, 'A'+i or lowercase for normal
+// On wild disappearance, Position = (BoardWidth, BoardHeight)
+
+// Order in which a wild tenant tries its neighbours when asked to relocate
+var wildEscapes = [...]board.Direction{board.Right, board.Left, board.Down, board.Up}
+
+// traveler is the life of traveler id
+func (w *world) traveler(id int, sym rune, startCh <-chan struct{}, seed int64, spec *scenario.Traveler) {
+	r := rand.New(rand.NewSource(seed))
+
+	// INIT phase
+	var pos board.Position
+	for {
+		if w.sim.Stopped() {
+			return // the cells no longer answer
+		}
+		pos = spec.StartOr(func() board.Position { return w.board.RandomPosition(r) })
+		if w.board.Cell(pos).Request().CanOccupy {
+			w.board.Cell(pos).Occupy(id)
+			break
+		}
+		w.clock.Sleep(1 * time.Millisecond)
+	}
+
+	steps := spec.StepsOr(func() int { return w.cfg.MinSteps + r.Intn(w.cfg.MaxSteps-w.cfg.MinSteps+1) })
+
+	traces := make([]board.Trace, 0, steps+1)
+	record := func(sym rune, ev board.Event) {
+		traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: sym, Event: ev}))
+	}
+	record(sym, board.Move)
+
+	<-startCh
+
+	for step := 0; step < steps && !w.sim.Stopped(); step++ {
+		d := w.cfg.MinDelay + time.Duration(r.Float64()*float64(w.cfg.MaxDelay-w.cfg.MinDelay))
+		w.clock.Sleep(d)
+
+		var newPos board.Position
+		if d, ok := spec.Direction(); ok {
+			newPos = w.board.Move(pos, d)
+		} else {
+			newPos = w.board.RandomStep(pos, r)
+		}
+
+		startAttempt := w.clock.Now()
+		attempts := 0
+		moved := false
+		stuck := w.policy.Aborted(id) // wounded by an older traveler
+		// the cells stop serving once the run is stopped
+		for !stuck && !w.sim.Stopped() {
+			status := w.board.Cell(newPos).Request()
+			if status.CanOccupy {
+				w.board.Cell(pos).Free()
+				w.board.Cell(newPos).Occupy(id)
+				pos = newPos
+				moved = true
+				break
+			} else if status.Occupant == board.Wild {
+				// occupied by wild: request relocation
+				response := make(chan bool)
+				select {
+				case status.WildMoveReq <- response:
+				case <-status.WildGone:
+					// the tenant left meanwhile, ask the cell again
+					continue
+				}
+				if <-response {
+					continue
+				}
+				// wild couldn't move: choose new direction
+				newPos = w.board.RandomStep(newPos, r)
+			} else if w.clock.Now()-startAttempt > w.cfg.MaxDelay {
+				// blocked by a traveler: ask the policy
+				attempts++
+				res := w.policy.Resolve(board.Conflict{Traveler: id, Holder: status.Id, Attempts: attempts, Rand: r})
+				switch res.Action {
+				case board.GiveUp:
+					stuck = true
+					continue
+				case board.Redirect:
+					newPos = w.board.Detour(pos, newPos, r)
+				}
+				w.clock.Sleep(res.Delay)
+				startAttempt = w.clock.Now()
+			} else {
+				w.clock.Sleep(1 * time.Millisecond)
+			}
+		}
+
+		if stuck {
+			sym = rune(int(sym) + 32)
+			record(sym, board.Deadlock)
+			w.policy.GaveUp()
+			break
+		}
+		if !moved {
+			break // stopped while waiting for the cell
+		}
+		w.policy.Moved()
+		record(sym, board.Move)
+	}
+
+	w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+}
+
+// wildTraveler is the life of wild tenant id
+func (w *world) wildTraveler(id int, seed int64, spec *scenario.Wild) {
+	r := rand.New(rand.NewSource(seed))
+
+	// appear at the time given by the scenario
+	w.clock.Sleep(spec.SpawnTime() - w.clock.Now())
+
+	var pos board.Position
+	for {
+		if w.sim.Stopped() {
+			return // the cells no longer answer
+		}
+		pos = spec.StartOr(func() board.Position { return w.board.RandomPosition(r) })
+		if w.board.Cell(pos).Request().CanOccupy {
+			break
+		}
+		w.clock.Sleep(1 * time.Millisecond)
+	}
+
+	moveReq := make(chan chan bool)
+	gone := make(chan struct{}) // closed when the tenant no longer answers
+	defer close(gone)
+	w.board.Cell(pos).OccupyWild(id, moveReq, gone)
+
+	symbol := spec.SymbolOr(func() rune { return rune('0' + r.Intn(10)) })
+	traces := []board.Trace{w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: symbol, Event: board.Move})}
+
+	lifespan := spec.LifespanOr(func() time.Duration {
+		return w.cfg.WildMinLifespan + time.Duration(r.Float64()*float64(w.cfg.WildMaxLifespan-w.cfg.WildMinLifespan))
+	})
+	end := w.clock.After(lifespan)
+	defer end.Stop()
+
+	for {
+		// wait for a relocation request or the end of life; a request
+		// comes from a running traveler, so the tenant runs on its behalf
+		w.clock.Park()
+		select {
+		case respCh := <-moveReq:
+			w.clock.Unpark()
+			moved := false
+			// try neighbor cells
+			for _, d := range wildEscapes {
+				temp := w.board.Move(pos, d)
+				if w.board.Cell(temp).Request().CanOccupy {
+					w.board.Cell(pos).Free()
+					w.board.Cell(temp).OccupyWild(id, moveReq, gone)
+					pos = temp
+					moved = true
+					break
+				}
+			}
+			respCh <- moved
+			ev := board.Blocked
+			if moved {
+				ev = board.Relocated
+			}
+			traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: symbol, Event: ev}))
+		case <-end.C:
+			w.board.Cell(pos).Free()
+			// disappearance
+			traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: w.board.Hidden(), Symbol: symbol, Event: board.Expired}))
+			w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+			return
+		}
+	}
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	cfg := m.Config
+	cfg.NrOfTraps = 0 // no traps here
+	if err := cfg.ValidateExclusive(); err != nil {
+		return err
+	}
+	if err := m.Scenario.Validate(&cfg, true); err != nil {
+		return err
+	}
+	policy, err := board.NewResolver(m.Policy, cfg.MaxDelay)
+	if err != nil {
+		return err
+	}
+
+	// initialize board, its cells serve until the run ends or is stopped
+	w := &world{cfg: cfg, board: cfg.Board(), clock: r.Clock, policy: policy, sim: r}
+	w.board.ServeCells(r.Context)
+	defer w.board.Close()
+
+	header := trace.Header{
+		Travelers: cfg.NrOfTravelers,
+		Width:     w.board.Width,
+		Height:    w.board.Height,
+		Wild:      trace.IDRange{First: cfg.NrOfTravelers, Count: cfg.NrOfWildSpawns},
+		Params:    map[string]string{"POLICY": policy.Name},
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+	w.printer = r.NewPrinter(cfg.NrOfTravelers + cfg.NrOfWildSpawns)
+	w.printer.Start()
+
+	startCh := make(chan struct{})
+
+	// launch normal travelers
+	for i := 0; i < cfg.NrOfTravelers; i++ {
+		travelerSeed := r.Seed.Derive("traveler", i)
+		r.Clock.Go(func() {
+			spec := m.Scenario.Traveler(i)
+			w.traveler(i, spec.SymbolOr(rune('A'+i)), startCh, travelerSeed, spec)
+		})
+	}
+	// launch wild travelers
+	for i := 0; i < cfg.NrOfWildSpawns; i++ {
+		wildSeed := r.Seed.Derive("wild", i)
+		r.Clock.Go(func() {
+			w.wildTraveler(cfg.NrOfTravelers+i, wildSeed, m.Scenario.WildSpawn(i))
+		})
+	}
+
+	// start normals
+	close(startCh)
+
+	r.Clock.Wait()
+	w.printer.Stop()
+	params := policy.Stats().Params()
+	if c := w.board.Contention(); len(c) > 0 {
+		params["CONTENTION"] = c.Param()
+	}
+	return r.End(params)
+}
@@ -0,0 +1,42 @@
+// Command zad4go prints the trace of a run of the traps simulation of
+// lista 2, see package traps.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/lista2/zad4go/traps"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := traps.New()
+	m.Config.RegisterFlags(flag.CommandLine)
+	m.Config.RegisterWildFlags(flag.CommandLine)
+	m.Config.RegisterTrapFlags(flag.CommandLine)
+	scenarioFile := flag.String("scenario", "", "JSON or YAML file describing the run")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	journalFlags := sched.RegisterJournal(flag.CommandLine)
+	policy := board.RegisterPolicy(flag.CommandLine)
+	flag.Parse()
+	m.Policy = *policy
+	var err error
+	if m.Scenario, err = scenario.Setup(&m.Config, *scenarioFile, true); err == nil {
+		m.Journal, err = journalFlags.Open(*flags.Clock)
+	}
+	if err == nil {
+		_, err = flags.Run(m)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := m.Journal.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,368 @@
+// Package traps is the travelers simulation of lista 2 with wild tenants
+// and traps: a traveler entering a trap turns lowercase and stops, a
+// tenant turns into '*' and vanishes, and the trap reports every catch.
+package traps
+
+import (
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/scenario"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Config   board.Config
+	Scenario *scenario.Scenario // explicit setup applied to Config, may be nil
+	Policy   string             // what a blocked traveler does, see board.NewPolicy
+	Journal  *sched.Journal     // scheduling decisions recorded or replayed, nil for a free run
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Config: board.DefaultConfig(), Policy: board.Policies[0]}
+}
+
+// world is the state of a single run, shared by its travelers and tenants
+type world struct {
+	cfg     board.Config
+	board   *board.Board
+	clock   sched.Clock
+	journal *sched.Journal
+	policy  *board.Resolver
+	printer *board.Printer
+	sim     *sim.Run
+}
+
+// Order in which a wild tenant tries its neighbours when asked to relocate
+var wildEscapes = [...]board.Direction{board.Right, board.Left, board.Down, board.Up}
+
+// trapTrace is the single trace a trap reports on every state change ─── TRAP
+func trapTrace(id int, pos board.Position) board.Trace {
+	return board.Trace{Id: id, Position: pos, Symbol: '#', Event: board.StateChange}
+}
+
+// reportTrap reports a trace of a trap on its own
+func (w *world) reportTrap(t board.Trace) {
+	w.printer.Report(board.TracesSequence{Id: t.Id, Traces: []board.Trace{t}})
+}
+
+// traveler is the life of traveler id
+func (w *world) traveler(id int, sym rune, startCh <-chan struct{}, seed int64, spec *scenario.Traveler) {
+	r := rand.New(rand.NewSource(seed))
+	cells := w.board.Actor(id)
+
+	// INIT phase
+	var pos board.Position
+	for {
+		if w.sim.Stopped() {
+			return // the cells no longer answer
+		}
+		pos = spec.StartOr(func() board.Position { return w.board.RandomPosition(r) })
+		status := cells.Request(pos)
+		if status.CanOccupy && !status.IsTrap { // can't start on trap ─── TRAP
+			cells.Occupy(pos)
+			break
+		}
+		w.clock.Sleep(1 * time.Millisecond)
+	}
+
+	steps := spec.StepsOr(func() int { return w.cfg.MinSteps + r.Intn(w.cfg.MaxSteps-w.cfg.MinSteps+1) })
+	traces := make([]board.Trace, 0, steps+1)
+	record := func(sym rune, ev board.Event) {
+		traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: sym, Event: ev}))
+	}
+	record(sym, board.Move)
+
+	<-startCh
+
+	for step := 0; step < steps && !w.sim.Stopped(); step++ {
+		d := w.cfg.MinDelay + time.Duration(r.Float64()*float64(w.cfg.MaxDelay-w.cfg.MinDelay))
+		w.clock.Sleep(d)
+
+		var newPos board.Position
+		if d, ok := spec.Direction(); ok {
+			newPos = w.board.Move(pos, d)
+		} else {
+			newPos = w.board.RandomStep(pos, r)
+		}
+
+		startAttempt := w.clock.Now()
+		attempts := 0
+		moved := false
+		var stuck bool
+		w.journal.Do(id, "aborted", func() { stuck = w.policy.Aborted(id) }) // wounded by an older traveler
+		// the cells stop serving once the run is stopped
+		for !stuck && !w.sim.Stopped() {
+			status := cells.Request(newPos)
+			if status.CanOccupy {
+				// stepping into a trap? ─── TRAP
+				if status.IsTrap {
+					// mark lowercase, block and exit
+					sym = rune(int(sym) + 32)
+					cells.Occupy(newPos)
+					record(sym, board.Trapped)
+					w.clock.Sleep(w.cfg.TrapBlockTime)
+					cells.Free(newPos)
+					w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+					w.reportTrap(w.sim.Stamp(trapTrace(status.TrapId, newPos)))
+					return
+				}
+				// normal move
+				cells.Free(pos)
+				cells.Occupy(newPos)
+				pos = newPos
+				moved = true
+				break
+			} else if status.Occupant == board.Wild {
+				// occupied by wild
+				resp := make(chan bool)
+				select {
+				case status.WildMoveReq <- resp:
+				case <-status.WildGone:
+					// the tenant left meanwhile, ask the cell again
+					continue
+				}
+				if <-resp {
+					continue
+				}
+				// choose another direction
+				newPos = w.board.RandomStep(newPos, r)
+			} else if w.journal.Decide(id, "timeout", func() bool {
+				return w.clock.Now()-startAttempt > w.cfg.MaxDelay
+			}, func(bool) {}) {
+				// blocked by a traveler, or a tenant caught in a trap: ask the policy
+				attempts++
+				c := board.Conflict{Traveler: id, Holder: status.Id, Attempts: attempts, Rand: r}
+				if c.Holder >= w.cfg.NrOfTravelers {
+					c.Holder = -1
+				}
+				var res board.Resolution
+				w.journal.Do(id, "resolve", func() { res = w.policy.Resolve(c) })
+				switch res.Action {
+				case board.GiveUp:
+					stuck = true
+					continue
+				case board.Redirect:
+					newPos = w.board.Detour(pos, newPos, r)
+				}
+				w.clock.Sleep(res.Delay)
+				startAttempt = w.clock.Now()
+			} else {
+				w.clock.Sleep(1 * time.Millisecond)
+			}
+		}
+
+		if stuck {
+			sym = rune(int(sym) + 32)
+			record(sym, board.Deadlock)
+			w.policy.GaveUp()
+			break
+		}
+		if !moved {
+			break // stopped while waiting for the cell
+		}
+		w.policy.Moved()
+		record(sym, board.Move)
+	}
+
+	w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+}
+
+// wildTraveler is the life of wild tenant id
+func (w *world) wildTraveler(id int, seed int64, spec *scenario.Wild) {
+	r := rand.New(rand.NewSource(seed))
+	cells := w.board.Actor(id)
+
+	// appear at the time given by the scenario
+	w.clock.Sleep(spec.SpawnTime() - w.clock.Now())
+
+	// INIT phase, avoid traps ─── TRAP
+	var pos board.Position
+	for {
+		if w.sim.Stopped() {
+			return // the cells no longer answer
+		}
+		pos = spec.StartOr(func() board.Position { return w.board.RandomPosition(r) })
+		status := cells.Request(pos)
+		if status.CanOccupy && !status.IsTrap {
+			break
+		}
+		w.clock.Sleep(1 * time.Millisecond)
+	}
+
+	moveReq := make(chan chan bool)
+	gone := make(chan struct{}) // closed when the tenant no longer answers
+	defer close(gone)
+	cells.OccupyWild(pos, moveReq, gone)
+
+	symbol := spec.SymbolOr(func() rune { return rune('0' + r.Intn(10)) })
+	traces := []board.Trace{w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: symbol, Event: board.Move})}
+
+	lifespan := spec.LifespanOr(func() time.Duration {
+		return w.cfg.WildMinLifespan + time.Duration(r.Float64()*float64(w.cfg.WildMaxLifespan-w.cfg.WildMinLifespan))
+	})
+	end := w.clock.After(lifespan)
+	defer end.Stop()
+
+	for {
+		// wait for a relocation request or the end of life; a request
+		// comes from a running traveler, so the tenant runs on its behalf
+		var respCh chan bool
+		expired := w.journal.Decide(id, "expire", func() bool {
+			w.clock.Park()
+			select {
+			case respCh = <-moveReq:
+				w.clock.Unpark()
+				return false
+			case <-end.C:
+				return true
+			}
+		}, func(expired bool) {
+			if !expired {
+				respCh = <-moveReq
+			}
+		})
+		if expired {
+			cells.Free(pos)
+			traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: w.board.Hidden(), Symbol: symbol, Event: board.Expired}))
+			w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+			return
+		}
+
+		moved := false
+		for _, d := range wildEscapes {
+			temp := w.board.Move(pos, d)
+			status := cells.Request(temp)
+			if status.CanOccupy {
+				// if trap, symbol "*", block, then exit ─── TRAP
+				if status.IsTrap {
+					symbol = '*'
+					cells.Free(pos)
+					// a trapped tenant can no longer be asked to relocate
+					cells.Occupy(temp)
+					pos = temp
+					// the end of life must not fire while blocked
+					end.Stop()
+					// the block starts before the traveler is released,
+					// which keeps the virtual schedule deterministic
+					blocked := w.clock.After(w.cfg.TrapBlockTime)
+					respCh <- true
+					traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: symbol, Event: board.Trapped}))
+					w.clock.Park()
+					<-blocked.C
+					cells.Free(pos)
+					w.printer.Report(board.TracesSequence{Id: id, Traces: traces})
+					w.reportTrap(w.sim.Stamp(trapTrace(status.TrapId, temp)))
+					return
+				}
+				// normal wild move
+				cells.Free(pos)
+				cells.OccupyWild(temp, moveReq, gone)
+				pos = temp
+				moved = true
+				break
+			}
+		}
+		respCh <- moved
+		ev := board.Blocked
+		if moved {
+			ev = board.Relocated
+		}
+		traces = append(traces, w.sim.Stamp(board.Trace{Id: id, Position: pos, Symbol: symbol, Event: ev}))
+	}
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	cfg := m.Config
+	if err := cfg.ValidateExclusive(); err != nil {
+		return err
+	}
+	if err := m.Scenario.Validate(&cfg, true); err != nil {
+		return err
+	}
+	policy, err := board.NewResolver(m.Policy, cfg.MaxDelay)
+	if err != nil {
+		return err
+	}
+
+	// initialize board, its cells serve until the run ends or is stopped
+	w := &world{cfg: cfg, board: cfg.Board(), clock: r.Clock, journal: m.Journal, policy: policy, sim: r}
+	w.board.ServeCells(r.Context)
+	defer w.board.Close()
+	if w.journal != nil {
+		w.board.SetJournal(w.journal)
+	}
+
+	header := trace.Header{
+		Travelers: cfg.NrOfTravelers,
+		Width:     w.board.Width,
+		Height:    w.board.Height,
+		Wild:      trace.IDRange{First: cfg.NrOfTravelers, Count: cfg.NrOfWildSpawns},
+		Traps:     trace.IDRange{First: -cfg.NrOfTraps, Count: cfg.NrOfTraps},
+		Params:    map[string]string{"POLICY": policy.Name},
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+	w.printer = r.NewPrinter(cfg.NrOfTravelers + cfg.NrOfWildSpawns + cfg.NrOfTraps)
+	w.printer.Start()
+
+	// place traps, where the scenario says or at random ─── TRAP
+	rnd := r.Seed.Rand("traps", 0)
+	trapPositions := m.Scenario.TrapPositions()
+	starts := m.Scenario.Starts() // a random trap there would keep its entity off the board
+	placed := 0 - cfg.NrOfTraps
+	for placed < 0 && !r.Stopped() {
+		var pos board.Position
+		if trapPositions != nil {
+			pos = trapPositions[cfg.NrOfTraps+placed]
+		} else if pos = w.board.RandomPosition(rnd); slices.Contains(starts, pos) {
+			continue
+		}
+		trap := w.board.Actor(placed)
+		// only place on empty, non-trap cell
+		if status := trap.Request(pos); status.CanOccupy && !status.IsTrap {
+			trap.SetTrap(pos)
+			t := trapTrace(placed, pos) // set at time 0
+			r.Publish(t)
+			w.reportTrap(t)
+			placed++
+		}
+	}
+
+	startCh := make(chan struct{})
+
+	// launch normal travelers
+	for i := 0; i < cfg.NrOfTravelers; i++ {
+		travelerSeed := r.Seed.Derive("traveler", i)
+		r.Clock.Go(func() {
+			spec := m.Scenario.Traveler(i)
+			w.traveler(i, spec.SymbolOr(rune('A'+i)), startCh, travelerSeed, spec)
+		})
+	}
+	// launch wild travelers
+	for i := 0; i < cfg.NrOfWildSpawns; i++ {
+		wildSeed := r.Seed.Derive("wild", i)
+		r.Clock.Go(func() {
+			w.wildTraveler(cfg.NrOfTravelers+i, wildSeed, m.Scenario.WildSpawn(i))
+		})
+	}
+
+	// start normals
+	close(startCh)
+
+	r.Clock.Wait()
+	w.printer.Stop()
+	params := policy.Stats().Params()
+	if c := w.board.Contention(); len(c) > 0 {
+		params["CONTENTION"] = c.Param()
+	}
+	return r.End(params)
+}
@@ -0,0 +1,94 @@
+// Package tournament builds a mutual-exclusion lock of n processes from
+// locks of two: the processes are the leaves of a binary tree, each
+// inner node a two-process lock, and a process wins the matches on the
+// path from its leaf up to the root to enter the critical section.
+// Lista 3 composes Dekker's and Peterson's algorithms this way.
+package tournament
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Lock is a mutual-exclusion lock of two processes, 0 and 1
+type Lock interface {
+	Lock(me int)
+	Unlock(me int)
+}
+
+// Tree is the tournament of n processes, levels from 1 at the leaves to
+// Levels at the root
+type Tree struct {
+	size  int    // leaves, n rounded up to a power of 2
+	nodes []Lock // heap order, the root at 1
+}
+
+// New returns the tree of n processes, its nodes made by newLock
+func New(n int, newLock func() Lock) *Tree {
+	if n < 2 {
+		panic(fmt.Sprintf("tournament: %d processes", n))
+	}
+	size := 1 << bits.Len(uint(n-1))
+	t := &Tree{size: size, nodes: make([]Lock, size)}
+	for i := 1; i < size; i++ {
+		t.nodes[i] = newLock()
+	}
+	return t
+}
+
+// Levels returns the number of matches a process wins to enter
+func (t *Tree) Levels() int {
+	return bits.Len(uint(t.size)) - 1
+}
+
+// Rows returns the labels of a process waiting at every level, label_1
+// at the leaves to label_L at the root
+func (t *Tree) Rows(label string) []string {
+	rows := make([]string, t.Levels())
+	for i := range rows {
+		rows[i] = fmt.Sprintf("%s_%d", label, i+1)
+	}
+	return rows
+}
+
+// entryProtocol is the row of ENTRY_PROTOCOL, after LOCAL_SECTION
+const entryProtocol = 1
+
+// Row returns the row of a process in state, the lista3 states numbered
+// from LOCAL_SECTION 0 in the order of their rows. The entry protocol
+// takes a row per level, see Rows, which moves the states after it up;
+// a nil Tree, a lock of two, keeps a row per state.
+func (t *Tree) Row(state int) int {
+	if t != nil && state > entryProtocol {
+		return state + t.Levels() - 1
+	}
+	return state
+}
+
+// node returns the lock process me competes for at level l and its side
+func (t *Tree) node(me, l int) (Lock, int) {
+	leaf := t.size + me
+	return t.nodes[leaf>>l], (leaf >> (l - 1)) & 1
+}
+
+// Lock is the entry protocol of process me, the matches won one by one
+// from the leaf up; onLevel, if not nil, is called as the process starts
+// waiting at every level above the first, where its trace moves a row up
+func (t *Tree) Lock(me int, onLevel func(l int)) {
+	for l := 1; l <= t.Levels(); l++ {
+		if l > 1 && onLevel != nil {
+			onLevel(l)
+		}
+		lock, side := t.node(me, l)
+		lock.Lock(side)
+	}
+}
+
+// Unlock is the exit protocol of process me, the matches it won released
+// from the root down
+func (t *Tree) Unlock(me int) {
+	for l := t.Levels(); l >= 1; l-- {
+		lock, side := t.node(me, l)
+		lock.Unlock(side)
+	}
+}
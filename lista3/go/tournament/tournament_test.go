@@ -0,0 +1,104 @@
+package tournament_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/TrollYuck/PW_INA_2025/check"
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/tournament"
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/zad4/dekker"
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/zad6/peterson"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/seed"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func newLock() tournament.Lock { return dekker.NewLock(sched.NewReal()) }
+
+func TestLevels(t *testing.T) {
+	for _, tt := range []struct{ n, levels int }{{2, 1}, {3, 2}, {4, 2}, {5, 3}, {15, 4}, {16, 4}, {17, 5}} {
+		if got := tournament.New(tt.n, newLock).Levels(); got != tt.levels {
+			t.Errorf("%d processes: %d levels, want %d", tt.n, got, tt.levels)
+		}
+	}
+	tree := tournament.New(3, newLock)
+	if rows, want := tree.Rows("ENTRY_PROTOCOL"), []string{"ENTRY_PROTOCOL_1", "ENTRY_PROTOCOL_2"}; !slices.Equal(rows, want) {
+		t.Errorf("Rows = %v, want %v", rows, want)
+	}
+}
+
+func TestRow(t *testing.T) {
+	var two *tournament.Tree // Dekker's or Peterson's lock alone
+	eight := tournament.New(8, newLock)
+	for state, want := range []int{0, 1, 4, 5} { // LOCAL_SECTION to EXIT_PROTOCOL
+		if got := two.Row(state); got != state {
+			t.Errorf("two processes: Row(%d) = %d, want %d", state, got, state)
+		}
+		if got := eight.Row(state); got != want {
+			t.Errorf("8 processes: Row(%d) = %d, want %d", state, got, want)
+		}
+	}
+}
+
+// free is a lock that never waits, recording the matches played
+type free struct{ played *[]int }
+
+func (f free) Lock(me int)   { *f.played = append(*f.played, me) }
+func (f free) Unlock(me int) {}
+
+func TestLockLevels(t *testing.T) {
+	var played, levels []int
+	tree := tournament.New(5, func() tournament.Lock { return free{&played} })
+	tree.Lock(5, func(l int) { levels = append(levels, l) })
+	// process 5, 101 in binary, plays on the right, left and right side
+	if want := []int{1, 0, 1}; !slices.Equal(played, want) {
+		t.Errorf("sides played = %v, want %v", played, want)
+	}
+	if want := []int{2, 3}; !slices.Equal(levels, want) {
+		t.Errorf("onLevel called for levels %v, want %v", levels, want)
+	}
+	tree.Lock(0, nil)
+}
+
+// TestMutualExclusion runs the tournaments of Dekker's and Peterson's
+// locks under the virtual clock and checks that no two processes were
+// ever in the critical section together
+func TestMutualExclusion(t *testing.T) {
+	models := []struct {
+		name string
+		new  func(n int) sim.Model
+	}{
+		{"dekker", func(n int) sim.Model {
+			m := dekker.New()
+			m.Processes, m.Tournament = n, true
+			return m
+		}},
+		{"peterson", func(n int) sim.Model {
+			m := peterson.New()
+			m.Processes, m.Tournament = n, true
+			return m
+		}},
+	}
+	for _, mt := range models {
+		for _, n := range []int{2, 3, 6, 15} {
+			for s := seed.Master(1); s <= 3; s++ {
+				sm := sim.Simulation{Model: mt.new(n), Seed: s, Clock: sched.KindVirtual}
+				res, err := sm.Run(context.Background())
+				if err != nil {
+					t.Fatalf("%s, %d processes, seed %v: %v", mt.name, n, s, err)
+				}
+				overlaps, err := check.MutualExclusion(res.File())
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, o := range overlaps {
+					t.Errorf("%s, %d processes, seed %v: %v", mt.name, n, s, o)
+				}
+				if len(res.Outcomes) != n {
+					t.Errorf("%s, %d processes, seed %v: %d in the trace", mt.name, n, s, len(res.Outcomes))
+				}
+			}
+		}
+	}
+}
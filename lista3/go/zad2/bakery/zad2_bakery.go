@@ -1,12 +1,19 @@
-package main
+// Package bakery is Lamport's bakery algorithm of lista 3: the processes
+// take numbered tickets and enter the critical section in ticket order.
+package bakery
 
 import (
-	"fmt"
 	"math/rand"
-	"os"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/fairness"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
 )
 
 const (
@@ -36,23 +43,38 @@ const (
 	boardHeight = int(ExitProtocol) + 1
 )
 
-// Timing
-var startTime time.Time
+// Model is the simulation, it is a sim.Model
+type Model struct{}
+
+// New returns the model
+func New() *Model {
+	return &Model{}
+}
+
+// world is the state of a single run, shared by its processes
+type world struct {
+	// Timing, real or simulated
+	clk sched.Clock
 
-// Shared variables for Bakery Algorithm
-var (
+	// Shared variables for Bakery Algorithm
 	choosing []int32 // Use int32 for atomic operations (0 or 1)
 	number   []int64 // Use int64 for ticket numbers
-)
 
-// Max_Ticket_Tracker
-var storedMaxTicket int64 // Accessed atomically
+	// Max_Ticket_Tracker
+	storedMaxTicket int64 // Accessed atomically
+
+	rows    []string // labels of the states
+	printed []Trace  // for the fairness in the footer
 
-func updateOverallMax(ticketValue int64) {
+	sim *sim.Run
+	err error // of the end of the trace
+}
+
+func (w *world) updateOverallMax(ticketValue int64) {
 	for {
-		oldMax := atomic.LoadInt64(&storedMaxTicket)
+		oldMax := atomic.LoadInt64(&w.storedMaxTicket)
 		if ticketValue > oldMax {
-			if atomic.CompareAndSwapInt64(&storedMaxTicket, oldMax, ticketValue) {
+			if atomic.CompareAndSwapInt64(&w.storedMaxTicket, oldMax, ticketValue) {
 				return
 			}
 		} else {
@@ -61,71 +83,53 @@ func updateOverallMax(ticketValue int64) {
 	}
 }
 
-func getOverallMax() int64 {
-	return atomic.LoadInt64(&storedMaxTicket)
+func (w *world) getOverallMax() int64 {
+	return atomic.LoadInt64(&w.storedMaxTicket)
 }
 
 // Position_Type
-type Position struct {
-	X int
-	Y int
-}
+type Position = board.Position
 
 // Trace_Type
-type Trace struct {
-	TimeStamp time.Duration
-	Id        int
-	Position  Position
-	Symbol    rune
-}
+type Trace = board.Trace
 
 // Traces_Sequence_Type
 type TracesSequence []Trace
 
-// Global random number generator for initial seed generation
-var globalRand = rand.New(rand.NewSource(time.Now().UnixNano()))
-
-func printTrace(trace Trace) {
-	fmt.Printf("%.9f %d %d %d %c\n",
-		trace.TimeStamp.Seconds(),
-		trace.Id,
-		trace.Position.X,
-		trace.Position.Y,
-		trace.Symbol,
-	)
+func (w *world) printTrace(t Trace) {
+	w.printed = append(w.printed, t)
+	w.sim.Print(t)
 }
 
-func printTraces(traces TracesSequence) {
+func (w *world) printTraces(traces TracesSequence) {
 	for _, trace := range traces {
-		printTrace(trace)
+		w.printTrace(trace)
 	}
 }
 
 // Printer task
-func printerTask(reportChan <-chan TracesSequence, wg *sync.WaitGroup) {
+func (w *world) printerTask(reportChan <-chan TracesSequence, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	// Loop nrOfProcesses times to receive and print traces
 	for i := 0; i < nrOfProcesses; i++ {
 		traces := <-reportChan
-		printTraces(traces)
+		w.printTraces(traces)
 		// Optionally, you could collect them if needed for other purposes:
 		// allTraces = append(allTraces, traces)
 	}
 
 	// Final parameter printing
-	fmt.Fprintf(os.Stdout, "-1 %d %d %d ", nrOfProcesses, boardWidth, boardHeight)
-	for i := ProcessState(0); i <= ExitProtocol; i++ {
-		fmt.Fprintf(os.Stdout, "%s;", i.String())
-	}
-	fmt.Fprintf(os.Stdout, "MAX_TICKET=%d;\n", getOverallMax())
+	params := fairness.Footer(w.rows, w.printed)
+	params["MAX_TICKET"] = strconv.FormatInt(w.getOverallMax(), 10)
+	w.err = w.sim.End(params)
 }
 
 // Helper Max function for Bakery Algorithm
-func bakeryMax() int64 {
+func (w *world) bakeryMax() int64 {
 	currentMax := int64(0)
 	for i := 0; i < nrOfProcesses; i++ {
-		num_i := atomic.LoadInt64(&number[i])
+		num_i := atomic.LoadInt64(&w.number[i])
 		if num_i > currentMax {
 			currentMax = num_i
 		}
@@ -141,16 +145,13 @@ type processInfo struct {
 }
 
 // Process_Task_Type
-func processTask(
+func (w *world) processTask(
 	id int,
 	seed int64,
 	symbol rune,
-	wg *sync.WaitGroup,
 	reportChan chan<- TracesSequence,
 	startSignal <-chan struct{}, // To synchronize start
 ) {
-	defer wg.Done()
-
 	localRand := rand.New(rand.NewSource(seed))
 	process := processInfo{
 		Id:     id,
@@ -165,14 +166,13 @@ func processTask(
 	var myHighestTicket int64 = 0
 
 	storeTrace := func(state ProcessState) {
-		ts := time.Since(startTime)
 		process.Position.Y = int(state)
-		traces = append(traces, Trace{
-			TimeStamp: ts,
-			Id:        process.Id,
-			Position:  process.Position,
-			Symbol:    process.Symbol,
-		})
+		traces = append(traces, w.sim.Stamp(Trace{
+			Id:       process.Id,
+			Position: process.Position,
+			Symbol:   process.Symbol,
+			Event:    board.StateChange,
+		}))
 	}
 
 	// Initial trace
@@ -193,92 +193,111 @@ func processTask(
 	}
 
 	for range iterations {
+		if w.sim.Stopped() {
+			break
+		}
+
 		// LOCAL_SECTION
 		delay := minDelayMs + localRand.Intn(maxDelayMs-minDelayMs+1)
-		time.Sleep(time.Duration(delay) * time.Millisecond)
+		w.clk.Sleep(time.Duration(delay) * time.Millisecond)
 
 		// ENTRY_PROTOCOL
 		storeTrace(EntryProtocol)
-		atomic.StoreInt32(&choosing[process.Id], 1)
+		atomic.StoreInt32(&w.choosing[process.Id], 1)
 
-		newTicket := 1 + bakeryMax()
-		atomic.StoreInt64(&number[process.Id], newTicket)
+		newTicket := 1 + w.bakeryMax()
+		atomic.StoreInt64(&w.number[process.Id], newTicket)
 		if newTicket > myHighestTicket {
 			myHighestTicket = newTicket
 		}
-		atomic.StoreInt32(&choosing[process.Id], 0)
+		atomic.StoreInt32(&w.choosing[process.Id], 0)
 
 		for j := range nrOfProcesses {
 			if j == process.Id {
 				continue
 			}
 			// Wait for choosing[j] to be 0
-			for atomic.LoadInt32(&choosing[j]) == 1 {
-				time.Sleep(1 * time.Microsecond) // Small sleep
+			for atomic.LoadInt32(&w.choosing[j]) == 1 {
+				w.clk.Yield() // Small sleep
 			}
 			// Wait for number[j] to be 0, or for (number[id], id) < (number[j], j)
 			for {
-				numJ := atomic.LoadInt64(&number[j])
-				numID := atomic.LoadInt64(&number[process.Id])
+				numJ := atomic.LoadInt64(&w.number[j])
+				numID := atomic.LoadInt64(&w.number[process.Id])
 
 				if numJ == 0 || (numID < numJ) || (numID == numJ && process.Id < j) {
 					break
 				}
-				time.Sleep(1 * time.Microsecond) // Small sleep
+				w.clk.Yield() // Small sleep
 			}
 		}
 
 		// CRITICAL_SECTION
 		storeTrace(CriticalSection)
 		delay = minDelayMs + localRand.Intn(maxDelayMs-minDelayMs+1)
-		time.Sleep(time.Duration(delay) * time.Millisecond)
+		w.clk.Sleep(time.Duration(delay) * time.Millisecond)
 
 		// EXIT_PROTOCOL
 		storeTrace(ExitProtocol)
-		atomic.StoreInt64(&number[process.Id], 0)
+		atomic.StoreInt64(&w.number[process.Id], 0)
 
 		// Back to LOCAL_SECTION for the next iteration
 		storeTrace(LocalSection)
 	}
 
-	updateOverallMax(myHighestTicket)
+	w.updateOverallMax(myHighestTicket)
 	reportChan <- traces
 }
 
-func main() {
-	startTime = time.Now()
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	w := &world{clk: r.Clock, sim: r}
+
+	var rows []string
+	for i := ProcessState(0); i <= ExitProtocol; i++ {
+		rows = append(rows, i.String())
+	}
+	w.rows = rows
+	header := trace.Header{
+		Travelers: nrOfProcesses,
+		Width:     boardWidth,
+		Height:    boardHeight,
+		Rows:      rows,
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
 
-	choosing = make([]int32, nrOfProcesses)
-	number = make([]int64, nrOfProcesses)
+	w.choosing = make([]int32, nrOfProcesses)
+	w.number = make([]int64, nrOfProcesses)
 
-	var wgProcesses sync.WaitGroup
 	var wgPrinter sync.WaitGroup
 
 	reportChan := make(chan TracesSequence) // Unbuffered to ensure printer processes one by one
 
 	// Start Printer task
 	wgPrinter.Add(1)
-	go printerTask(reportChan, &wgPrinter) // Call printerTask as a goroutine
+	go w.printerTask(reportChan, &wgPrinter) // Call printerTask as a goroutine
 
 	startSignal := make(chan struct{}) // Channel to synchronize the start of process tasks
 
 	// Init and Start Process Tasks
 	for i := 0; i < nrOfProcesses; i++ {
-		wgProcesses.Add(1)
 		// Ada: Seeds(I+1) - assuming 1-indexed seeds array.
-		// Go: generate a unique seed for each.
-		seed := time.Now().UnixNano() + int64(i*100) // Simple unique seed
+		// Go: derive the seed of each process from the master seed.
+		processSeed := r.Seed.Derive("process", i)
 		symbol := rune('A' + i)
-		go processTask(i, seed, symbol, &wgProcesses, reportChan, startSignal)
+		r.Clock.Go(func() { w.processTask(i, processSeed, symbol, reportChan, startSignal) })
 	}
 
 	// Signal all process tasks to start after they are initialized
 	close(startSignal)
 
 	// Wait for all process tasks to complete
-	wgProcesses.Wait()
+	r.Clock.Wait()
 
 	// All process tasks have sent their reports.
 	// Now, wait for the printerTask goroutine to finish processing all reports and printing the footer.
 	wgPrinter.Wait()
+	return w.err
 }
@@ -0,0 +1,21 @@
+// Command zad2 prints the trace of a run of Lamport's bakery algorithm of lista 3,
+// see package bakery.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/zad2/bakery"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	flags := sim.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if _, err := flags.Run(bakery.New()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
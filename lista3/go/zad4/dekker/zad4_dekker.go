@@ -0,0 +1,285 @@
+// Package dekker is Dekker's algorithm of lista 3, mutual exclusion of
+// two processes, and a tournament of its locks for n processes, see
+// package tournament.
+package dekker
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/fairness"
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/tournament"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+const (
+	nrOfProcesses = 2
+	minSteps      = 50
+	maxSteps      = 100
+	minDelay      = 10 * time.Millisecond
+	maxDelay      = 50 * time.Millisecond
+)
+
+type ProcessState int
+
+const (
+	LocalSection ProcessState = iota
+	EntryProtocol
+	CriticalSection
+	ExitProtocol
+)
+
+func (ps ProcessState) String() string {
+	return [...]string{"LOCAL_SECTION", "ENTRY_PROTOCOL", "CRITICAL_SECTION", "EXIT_PROTOCOL"}[ps]
+}
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Processes  int  // number of processes, 2 unless Tournament
+	Tournament bool // use a tournament of Dekker's locks, for any number of processes
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Processes: nrOfProcesses}
+}
+
+// Lock is Dekker's algorithm, a tournament.Lock
+type Lock struct {
+	// Timing, real or simulated
+	clk sched.Clock
+
+	// Dekker's Algorithm Shared Variables
+	// want[i] is 1 if process i wants to enter, 0 otherwise.
+	want [2]int32
+
+	// turn is the ID of the process whose turn it is.
+	turn int32 // 0 or 1
+}
+
+// NewLock returns the lock, its processes wait on clk
+func NewLock(clk sched.Clock) *Lock {
+	return &Lock{clk: clk}
+}
+
+// Lock is Dekker's Entry Protocol for process me, 0 or 1
+func (l *Lock) Lock(me int) {
+	other := int32(1 - me)
+	atomic.StoreInt32(&l.want[me], 1) // I want to enter (true)
+	for atomic.LoadInt32(&l.want[other]) == 1 {
+		if atomic.LoadInt32(&l.turn) == other {
+			atomic.StoreInt32(&l.want[me], 0)
+			for atomic.LoadInt32(&l.turn) == other {
+				l.clk.Yield()
+			}
+			atomic.StoreInt32(&l.want[me], 1) // Re-assert my intention, it's my turn now (true)
+		} else {
+			l.clk.Yield()
+		}
+	}
+}
+
+// Unlock is Dekker's Exit Protocol for process me
+func (l *Lock) Unlock(me int) {
+	atomic.StoreInt32(&l.turn, int32(1-me))
+	atomic.StoreInt32(&l.want[me], 0)
+}
+
+// world is the state of a single run, shared by its processes
+type world struct {
+	// Timing, real or simulated
+	clk sched.Clock
+
+	n     int              // number of processes
+	mutex *Lock            // of the two processes, nil in a tournament
+	tree  *tournament.Tree // nil for two processes
+
+	rows    []string // labels of the states
+	printed []Trace  // for the fairness in the footer
+
+	sim *sim.Run
+	err error // of the end of the trace
+}
+
+type Position = board.Position
+
+type Trace = board.Trace
+
+func (w *world) printTrace(t Trace) {
+	w.printed = append(w.printed, t)
+	w.sim.Print(t)
+}
+
+// printTraces prints all traces for a process.
+func (w *world) printTraces(traces []Trace) {
+	for _, t := range traces {
+		w.printTrace(t)
+	}
+}
+
+// printerTask collects traces from all processes and prints them, then prints the summary line.
+func (w *world) printerTask(traceChan <-chan []Trace, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	allProcessTraces := make([][]Trace, w.n)
+	for range w.n {
+		processTraces := <-traceChan // Receive traces from a process
+		if len(processTraces) > 0 {
+			processID := processTraces[0].Id
+			if processID >= 0 && processID < w.n {
+				allProcessTraces[processID] = processTraces
+			}
+		}
+	}
+
+	for i := range w.n {
+		if allProcessTraces[i] != nil {
+			w.printTraces(allProcessTraces[i])
+		}
+	}
+
+	w.err = w.sim.End(fairness.Footer(w.rows, w.printed))
+}
+
+type ProcessData struct {
+	ID       int
+	Symbol   rune
+	Position Position
+}
+
+// processTask simulates a single process executing Dekker's algorithm.
+func (w *world) processTask(id int, seed int64, symbol rune, traceChan chan<- []Trace) {
+	r := rand.New(rand.NewSource(seed))
+	process := ProcessData{
+		ID:     id,
+		Symbol: symbol,
+		Position: Position{
+			X: id,
+			Y: int(LocalSection),
+		},
+	}
+
+	var traces []Trace
+
+	storeTrace := func() {
+		traces = append(traces, w.sim.Stamp(Trace{
+			Id:       process.ID,
+			Position: process.Position,
+			Symbol:   process.Symbol,
+			Event:    board.StateChange,
+		}))
+	}
+
+	changeState := func(state ProcessState) {
+		process.Position.Y = w.tree.Row(int(state))
+		storeTrace()
+	}
+
+	storeTrace()
+
+	baseNrOfSteps := minSteps + r.Intn(maxSteps-minSteps+1)
+	loopIterations := baseNrOfSteps / 4
+
+	for step := 0; step < loopIterations && !w.sim.Stopped(); step++ {
+		// LOCAL_SECTION
+		delayNs := float64(minDelay.Nanoseconds()) + r.Float64()*float64((maxDelay-minDelay).Nanoseconds())
+		w.clk.Sleep(time.Duration(delayNs))
+
+		changeState(EntryProtocol)
+
+		if w.tree == nil {
+			w.mutex.Lock(id)
+		} else {
+			w.tree.Lock(id, func(int) {
+				process.Position.Y++
+				storeTrace()
+			})
+		}
+
+		changeState(CriticalSection)
+
+		// CRITICAL_SECTION
+		delayNs = float64(minDelay.Nanoseconds()) + r.Float64()*float64((maxDelay-minDelay).Nanoseconds())
+		w.clk.Sleep(time.Duration(delayNs))
+
+		changeState(ExitProtocol)
+
+		if w.tree == nil {
+			w.mutex.Unlock(id)
+		} else {
+			w.tree.Unlock(id)
+		}
+
+		changeState(LocalSection)
+	}
+
+	traceChan <- traces
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	n := m.Processes
+	switch {
+	case n < 2:
+		return errors.New("dekker: at least 2 processes")
+	case n != 2 && !m.Tournament:
+		return fmt.Errorf("dekker: Dekker's algorithm is for 2 processes, use a tournament for %d", n)
+	}
+	w := &world{clk: r.Clock, n: n, sim: r}
+	if m.Tournament {
+		w.tree = tournament.New(n, func() tournament.Lock { return NewLock(r.Clock) })
+	} else {
+		w.mutex = NewLock(r.Clock)
+	}
+
+	var stateLabels []string
+	for i := LocalSection; i <= ExitProtocol; i++ {
+		if i == EntryProtocol && w.tree != nil {
+			stateLabels = append(stateLabels, w.tree.Rows(i.String())...)
+			continue
+		}
+		stateLabels = append(stateLabels, i.String())
+	}
+	w.rows = stateLabels
+	header := trace.Header{
+		Travelers: n,
+		Width:     n,
+		Height:    len(stateLabels),
+		Rows:      stateLabels,
+		Extra:     []string{"EXTRA_LABEL"},
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+
+	seeds := make([]int64, n)
+	for i := range n {
+		seeds[i] = r.Seed.Derive("process", i)
+	}
+
+	traceChan := make(chan []Trace, n)
+
+	var printerWg sync.WaitGroup
+
+	printerWg.Add(1)
+	go w.printerTask(traceChan, &printerWg)
+
+	currentSymbol := 'A'
+	for i := range n {
+		symbol := currentSymbol
+		r.Clock.Go(func() { w.processTask(i, seeds[i], symbol, traceChan) })
+		currentSymbol++
+	}
+
+	r.Clock.Wait()
+	close(traceChan)
+	printerWg.Wait()
+	return w.err
+}
@@ -0,0 +1,24 @@
+// Command zad4 prints the trace of a run of Dekker's algorithm of lista 3,
+// or of a tournament tree of its locks with -tournament, see package dekker.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/zad4/dekker"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := dekker.New()
+	flag.IntVar(&m.Processes, "processes", m.Processes, "number of processes, more than 2 need -tournament")
+	flag.BoolVar(&m.Tournament, "tournament", false, "use a tournament tree of Dekker's locks for n processes")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if _, err := flags.Run(m); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
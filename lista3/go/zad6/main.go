@@ -0,0 +1,26 @@
+// Command zad6 prints the trace of a run of Peterson's algorithm of lista 3,
+// or of the filter lock with -filter or a tournament tree of Peterson's
+// locks with -tournament, see package peterson.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/zad6/peterson"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+func main() {
+	m := peterson.New()
+	flag.IntVar(&m.Processes, "processes", m.Processes, "number of processes, more than 2 need -filter or -tournament")
+	flag.BoolVar(&m.Filter, "filter", false, "use the filter lock, Peterson's algorithm for n processes")
+	flag.BoolVar(&m.Tournament, "tournament", false, "use a tournament tree of Peterson's locks for n processes")
+	flags := sim.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if _, err := flags.Run(m); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,364 @@
+// Package peterson is Peterson's algorithm of lista 3, mutual exclusion
+// of two processes, and its generalisation to n processes, the filter
+// lock: a process climbs n-1 levels, at each one Peterson's protocol
+// against all the processes at that level or higher holds back the last
+// to arrive, so at most n-L processes get past level L and one reaches
+// the critical section. A tournament of the locks of two processes is
+// another way to n, see package tournament.
+package peterson
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/fairness"
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/tournament"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+const (
+	nrOfProcesses = 2
+	minSteps      = 50
+	maxSteps      = 100
+	minDelay      = 10 * time.Millisecond
+	maxDelay      = 50 * time.Millisecond
+)
+
+// ProcessState enum
+type ProcessState int
+
+const (
+	LocalSection ProcessState = iota
+	EntryProtocol
+	CriticalSection
+	ExitProtocol
+)
+
+func (ps ProcessState) String() string {
+	return [...]string{"LOCAL_SECTION", "ENTRY_PROTOCOL", "CRITICAL_SECTION", "EXIT_PROTOCOL"}[ps]
+}
+
+// Model holds the parameters of the simulation, it is a sim.Model
+type Model struct {
+	Processes  int  // number of processes, 2 unless Filter or Tournament
+	Filter     bool // use the filter lock, for any number of processes
+	Tournament bool // use a tournament of Peterson's locks, for any number of processes
+}
+
+// New returns the model with the parameters of the assignment
+func New() *Model {
+	return &Model{Processes: nrOfProcesses}
+}
+
+// world is the state of a single run, shared by its processes
+type world struct {
+	// Timing, real or simulated
+	clk sched.Clock
+
+	n      int              // number of processes
+	filter bool             // use the filter lock
+	mutex  *Lock            // of the two processes, nil otherwise
+	tree   *tournament.Tree // nil unless a tournament
+
+	// Filter lock shared variables: the level each process is at, 0 in
+	// the local section, and the last process to arrive at each level
+	level   []atomic.Int32
+	victims []atomic.Int32
+
+	// reportChan for processes to send traces to the printer goroutine
+	reportChan chan TracesSequence
+	wgPrinter  sync.WaitGroup
+
+	rows    []string // labels of the states
+	printed []Trace  // for the fairness in the footer
+
+	sim *sim.Run
+	err error // of the end of the trace
+}
+
+// Position_Type
+type Position = board.Position
+
+// Trace_Type
+type Trace = board.Trace
+
+// Traces_Sequence_Type struct
+type TracesSequence struct {
+	Last       int
+	TraceArray []Trace
+}
+
+func (w *world) printTrace(t Trace) {
+	w.printed = append(w.printed, t)
+	w.sim.Print(t)
+}
+
+// Lock is Peterson's algorithm, a tournament.Lock
+type Lock struct {
+	// Timing, real or simulated
+	clk sched.Clock
+
+	// Peterson's Algorithm shared variables
+	interested [2]atomic.Bool
+
+	// victim indicates whose turn it is to wait if both are interested.
+	victim atomic.Int32
+}
+
+// NewLock returns the lock, its processes wait on clk
+func NewLock(clk sched.Clock) *Lock {
+	return &Lock{clk: clk}
+}
+
+// Lock is Peterson's Entry Protocol for process me, 0 or 1
+func (l *Lock) Lock(me int) {
+	other := 1 - me
+	l.interested[me].Store(true)
+	l.victim.Store(int32(me))
+	for l.interested[other].Load() && l.victim.Load() == int32(me) {
+		l.clk.Yield()
+	}
+}
+
+// Unlock is Peterson's Exit Protocol for process me
+func (l *Lock) Unlock(me int) {
+	l.interested[me].Store(false)
+}
+
+// Print_Traces
+func (w *world) printTraces(traces TracesSequence) {
+	for i := 0; i <= traces.Last; i++ {
+		w.printTrace(traces.TraceArray[i])
+	}
+}
+
+// Printer goroutine
+func (w *world) printerGoroutine() {
+	defer w.wgPrinter.Done()
+
+	for i := 0; i < w.n; i++ {
+		traces := <-w.reportChan
+		w.printTraces(traces)
+	}
+
+	w.err = w.sim.End(fairness.Footer(w.rows, w.printed))
+}
+
+// Process_Info
+type ProcessInfo struct {
+	ID       int
+	Symbol   rune
+	Position Position
+}
+
+// processGoroutine
+func (w *world) processGoroutine(id int, seed int64, symbol rune, startSignal <-chan struct{}) {
+	r := rand.New(rand.NewSource(seed))
+
+	process := ProcessInfo{
+		ID:     id,
+		Symbol: symbol,
+		Position: Position{
+			X: id,
+			Y: int(LocalSection),
+		},
+	}
+
+	traces := TracesSequence{
+		Last:       -1,
+		TraceArray: make([]Trace, maxSteps/4*len(w.rows)+1), // Max possible traces + initial
+	}
+
+	storeTrace := func() {
+		traces.Last++
+		if traces.Last < len(traces.TraceArray) {
+			traces.TraceArray[traces.Last] = w.sim.Stamp(Trace{
+				Id:       process.ID,
+				Position: process.Position,
+				Symbol:   process.Symbol,
+				Event:    board.StateChange,
+			})
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: Trace array overflow for process %d\n", process.ID)
+		}
+	}
+
+	changeState := func(state ProcessState) {
+		process.Position.Y = w.tree.Row(int(state))
+		storeTrace()
+	}
+
+	storeTrace()
+
+	totalStateChangesTarget := minSteps + int(float64(maxSteps-minSteps)*r.Float64())
+	numberOfCycles := totalStateChangesTarget / 4
+	if numberOfCycles == 0 && totalStateChangesTarget > 0 {
+		numberOfCycles = 1
+	}
+
+	<-startSignal
+
+	for range numberOfCycles {
+		if w.sim.Stopped() {
+			break
+		}
+
+		// LOCAL_SECTION
+		delayDuration := minDelay + time.Duration(float64(maxDelay-minDelay)*r.Float64())
+		w.clk.Sleep(delayDuration)
+
+		changeState(EntryProtocol)
+		if w.tree == nil {
+			w.lock(id)
+		} else {
+			w.tree.Lock(id, func(int) {
+				process.Position.Y++
+				storeTrace()
+			})
+		}
+
+		changeState(CriticalSection)
+		// CRITICAL_SECTION
+		delayDuration = minDelay + time.Duration(float64(maxDelay-minDelay)*r.Float64())
+		w.clk.Sleep(delayDuration)
+
+		changeState(ExitProtocol)
+		w.unlock(id)
+
+		changeState(LocalSection) // Back to local section
+	}
+
+	finalTraces := TracesSequence{
+		Last:       traces.Last,
+		TraceArray: traces.TraceArray[:traces.Last+1],
+	}
+	w.reportChan <- finalTraces
+}
+
+// lock is the entry protocol of process me, in a tournament the process
+// wins the levels one by one instead
+func (w *world) lock(me int) {
+	if w.filter {
+		w.filterLock(me)
+		return
+	}
+	w.mutex.Lock(me)
+}
+
+// unlock is the exit protocol of process me
+func (w *world) unlock(me int) {
+	switch {
+	case w.tree != nil:
+		w.tree.Unlock(me)
+	case w.filter:
+		// Filter lock Exit Protocol
+		w.level[me].Store(0)
+	default:
+		w.mutex.Unlock(me)
+	}
+}
+
+// filterLock is the entry protocol of the filter lock, Peterson's at
+// every level with all the others at least as high as interested
+func (w *world) filterLock(me int) {
+	for l := int32(1); l < int32(w.n); l++ {
+		w.level[me].Store(l)
+		w.victims[l].Store(int32(me))
+		for w.victims[l].Load() == int32(me) && w.higher(me, l) {
+			w.clk.Yield()
+		}
+	}
+}
+
+// higher tells whether a process other than me is at level l or above
+func (w *world) higher(me int, l int32) bool {
+	for k := range w.level {
+		if k != me && w.level[k].Load() >= l {
+			return true
+		}
+	}
+	return false
+}
+
+// Run carries out a run of the simulation
+func (m *Model) Run(r *sim.Run) error {
+	n := m.Processes
+	switch {
+	case n < 2:
+		return errors.New("peterson: at least 2 processes")
+	case m.Filter && m.Tournament:
+		return errors.New("peterson: either the filter lock or a tournament")
+	case n != 2 && !m.Filter && !m.Tournament:
+		return fmt.Errorf("peterson: Peterson's algorithm is for 2 processes, use the filter lock or a tournament for %d", n)
+	}
+	w := &world{clk: r.Clock, n: n, filter: m.Filter, reportChan: make(chan TracesSequence, n), sim: r}
+	switch {
+	case m.Filter:
+		w.level = make([]atomic.Int32, n)
+		w.victims = make([]atomic.Int32, n)
+	case m.Tournament:
+		w.tree = tournament.New(n, func() tournament.Lock { return NewLock(r.Clock) })
+	default:
+		w.mutex = NewLock(r.Clock)
+	}
+
+	var stateStrings []string
+	for i := LocalSection; i <= ExitProtocol; i++ {
+		if i == EntryProtocol && w.tree != nil {
+			stateStrings = append(stateStrings, w.tree.Rows(i.String())...)
+			continue
+		}
+		stateStrings = append(stateStrings, i.String())
+	}
+	w.rows = stateStrings
+	header := trace.Header{
+		Travelers: n,
+		Width:     n,
+		Height:    len(stateStrings),
+		Rows:      stateStrings,
+	}
+	if err := r.Begin(header); err != nil {
+		return err
+	}
+
+	seeds := make([]int64, n)
+	for i := range n {
+		seeds[i] = r.Seed.Derive("process", i)
+	}
+
+	// Start Printer goroutine
+	w.wgPrinter.Add(1)
+	go w.printerGoroutine()
+
+	// Create start signals for processes
+	startSignals := make([]chan struct{}, n)
+	for i := range n {
+		startSignals[i] = make(chan struct{})
+	}
+
+	// Start Process goroutines
+	currentSymbol := 'A'
+	for i := range n {
+		symbol := currentSymbol
+		r.Clock.Go(func() { w.processGoroutine(i, seeds[i], symbol, startSignals[i]) })
+		currentSymbol++
+	}
+
+	// Send start signals to all process goroutines
+	for i := range n {
+		close(startSignals[i]) // Closing channel broadcasts signal
+	}
+
+	r.Clock.Wait()
+
+	w.wgPrinter.Wait()
+	return w.err
+}
@@ -0,0 +1,47 @@
+package peterson_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TrollYuck/PW_INA_2025/check"
+	"github.com/TrollYuck/PW_INA_2025/lista3/go/zad6/peterson"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/seed"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+// TestFilterLock runs the filter lock under the virtual clock and checks
+// that no two processes were ever in the critical section together
+func TestFilterLock(t *testing.T) {
+	for _, n := range []int{2, 3, 5, 15} {
+		for s := seed.Master(1); s <= 3; s++ {
+			m := peterson.New()
+			m.Processes, m.Filter = n, true
+			sm := sim.Simulation{Model: m, Seed: s, Clock: sched.KindVirtual}
+			res, err := sm.Run(context.Background())
+			if err != nil {
+				t.Fatalf("%d processes, seed %v: %v", n, s, err)
+			}
+			overlaps, err := check.MutualExclusion(res.File())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, o := range overlaps {
+				t.Errorf("%d processes, seed %v: %v", n, s, o)
+			}
+			if len(res.Outcomes) != n {
+				t.Errorf("%d processes, seed %v: %d in the trace", n, s, len(res.Outcomes))
+			}
+		}
+	}
+}
+
+func TestTooManyProcesses(t *testing.T) {
+	m := peterson.New()
+	m.Processes = 3
+	sm := sim.Simulation{Model: m, Seed: 1, Clock: sched.KindVirtual}
+	if _, err := sm.Run(context.Background()); err == nil {
+		t.Error("Peterson's algorithm ran 3 processes")
+	}
+}
@@ -0,0 +1,100 @@
+package render
+
+import "strings"
+
+// A 5x7 bitmap font for the symbols of the traces and the captions of the
+// frames, the standard library having no fonts. Every glyph is its seven
+// rows, top to bottom, separated by slashes.
+var glyphRows = map[rune]string{
+	'A': ".###./#...#/#...#/#####/#...#/#...#/#...#",
+	'B': "####./#...#/#...#/####./#...#/#...#/####.",
+	'C': ".###./#...#/#..../#..../#..../#...#/.###.",
+	'D': "####./#...#/#...#/#...#/#...#/#...#/####.",
+	'E': "#####/#..../#..../####./#..../#..../#####",
+	'F': "#####/#..../#..../####./#..../#..../#....",
+	'G': ".###./#...#/#..../#.###/#...#/#...#/.####",
+	'H': "#...#/#...#/#...#/#####/#...#/#...#/#...#",
+	'I': ".###./..#../..#../..#../..#../..#../.###.",
+	'J': "..###/...#./...#./...#./...#./#..#./.##..",
+	'K': "#...#/#..#./#.#../##.../#.#../#..#./#...#",
+	'L': "#..../#..../#..../#..../#..../#..../#####",
+	'M': "#...#/##.##/#.#.#/#.#.#/#...#/#...#/#...#",
+	'N': "#...#/#...#/##..#/#.#.#/#..##/#...#/#...#",
+	'O': ".###./#...#/#...#/#...#/#...#/#...#/.###.",
+	'P': "####./#...#/#...#/####./#..../#..../#....",
+	'Q': ".###./#...#/#...#/#...#/#.#.#/#..#./.##.#",
+	'R': "####./#...#/#...#/####./#.#../#..#./#...#",
+	'S': ".####/#..../#..../.###./....#/....#/####.",
+	'T': "#####/..#../..#../..#../..#../..#../..#..",
+	'U': "#...#/#...#/#...#/#...#/#...#/#...#/.###.",
+	'V': "#...#/#...#/#...#/#...#/#...#/.#.#./..#..",
+	'W': "#...#/#...#/#...#/#.#.#/#.#.#/#.#.#/.#.#.",
+	'X': "#...#/#...#/.#.#./..#../.#.#./#...#/#...#",
+	'Y': "#...#/#...#/.#.#./..#../..#../..#../..#..",
+	'Z': "#####/....#/...#./..#../.#.../#..../#####",
+
+	'a': "...../...../.###./....#/.####/#...#/.####",
+	'b': "#..../#..../#.##./##..#/#...#/#...#/####.",
+	'c': "...../...../.###./#..../#..../#...#/.###.",
+	'd': "....#/....#/.##.#/#..##/#...#/#...#/.####",
+	'e': "...../...../.###./#...#/#####/#..../.###.",
+	'f': "..##./.#..#/.#.../###../.#.../.#.../.#...",
+	'g': "...../.####/#...#/#...#/.####/....#/.###.",
+	'h': "#..../#..../#.##./##..#/#...#/#...#/#...#",
+	'i': "..#../...../.##../..#../..#../..#../.###.",
+	'j': "...#./...../..##./...#./...#./#..#./.##..",
+	'k': "#..../#..../#..#./#.#../##.../#.#../#..#.",
+	'l': ".##../..#../..#../..#../..#../..#../.###.",
+	'm': "...../...../##.#./#.#.#/#.#.#/#...#/#...#",
+	'n': "...../...../#.##./##..#/#...#/#...#/#...#",
+	'o': "...../...../.###./#...#/#...#/#...#/.###.",
+	'p': "...../####./#...#/#...#/####./#..../#....",
+	'q': "...../.####/#...#/#...#/.####/....#/....#",
+	'r': "...../...../#.##./##..#/#..../#..../#....",
+	's': "...../...../.###./#..../.###./....#/####.",
+	't': ".#.../.#.../###../.#.../.#.../.#..#/..##.",
+	'u': "...../...../#...#/#...#/#...#/#..##/.##.#",
+	'v': "...../...../#...#/#...#/#...#/.#.#./..#..",
+	'w': "...../...../#...#/#...#/#.#.#/#.#.#/.#.#.",
+	'x': "...../...../#...#/.#.#./..#../.#.#./#...#",
+	'y': "...../#...#/#...#/#...#/.####/....#/.###.",
+	'z': "...../...../#####/...#./..#../.#.../#####",
+
+	'0': ".###./#...#/#..##/#.#.#/##..#/#...#/.###.",
+	'1': "..#../.##../..#../..#../..#../..#../.###.",
+	'2': ".###./#...#/....#/...#./..#../.#.../#####",
+	'3': "#####/...#./..#../...#./....#/#...#/.###.",
+	'4': "...#./..##./.#.#./#..#./#####/...#./...#.",
+	'5': "#####/#..../####./....#/....#/#...#/.###.",
+	'6': "..##./.#.../#..../####./#...#/#...#/.###.",
+	'7': "#####/....#/...#./..#../.#.../.#.../.#...",
+	'8': ".###./#...#/#...#/.###./#...#/#...#/.###.",
+	'9': ".###./#...#/#...#/.####/....#/...#./.##..",
+
+	'#': ".#.#./.#.#./#####/.#.#./#####/.#.#./.#.#.",
+	'*': "...../..#../#.#.#/.###./#.#.#/..#../.....",
+	'_': "...../...../...../...../...../...../#####",
+	'.': "...../...../...../...../...../.##../.##..",
+	'=': "...../...../#####/...../#####/...../.....",
+	'-': "...../...../...../#####/...../...../.....",
+	':': "...../.##../.##../...../.##../.##../.....",
+	'/': "...../....#/...#./..#../.#.../#..../.....",
+	' ': "...../...../...../...../...../...../.....",
+}
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyph returns the pixels of the glyph of r, a box for a rune the font
+// lacks
+func glyph(r rune) [glyphHeight]string {
+	rows, ok := glyphRows[r]
+	if !ok {
+		rows = "#####/#...#/#...#/#...#/#...#/#...#/#####"
+	}
+	var g [glyphHeight]string
+	copy(g[:], strings.Split(rows, "/"))
+	return g
+}
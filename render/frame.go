@@ -0,0 +1,142 @@
+// Package render draws a trace as pictures for the reports: an animated
+// GIF or APNG of the whole run, or an SVG per frame. A frame is the board
+// at a moment of the run, the frames being Options.Interval apart in the
+// time of the trace; every cell shows the symbol that arrived last, as in
+// distrav: traveler letters, wild tenant digits, traps and the lowercase
+// travelers that deadlocked or were trapped. The lista3 traces are drawn
+// the same way, a column per process and a row per state.
+package render
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Options of the frames and their playback
+type Options struct {
+	Interval time.Duration // time of the trace between frames, 0 for a hundredth of the run
+	Speed    float64       // playback speed, 1 for the time of the trace, 0 is 1
+	Cell     int           // side of a cell in pixels, 0 for DefaultCell
+}
+
+// DefaultCell is the side of a cell when Options.Cell is 0
+const DefaultCell = 20
+
+// MaxFrames bounds the number of frames of a trace
+const MaxFrames = 5000
+
+// Cell is a cell of the board in a frame
+type Cell struct {
+	Symbol rune // of the entity on top, 0 if the cell is empty
+	Trap   bool // a trap is set on the cell
+}
+
+// Frame is the board at a moment of the run
+type Frame struct {
+	Time  time.Duration
+	Cells [][]Cell // [y][x]
+}
+
+// Frames returns the frames of f at o.Interval from time 0, the last one
+// at or after the last trace
+func Frames(f *trace.File, o Options) ([]Frame, error) {
+	h := &f.Header
+	if h.Width <= 0 || h.Height <= 0 {
+		return nil, fmt.Errorf("trace has no board size")
+	}
+	traces := make([]board.Trace, len(f.Traces))
+	copy(traces, f.Traces)
+	// the programs print the traces of a traveler together
+	sort.SliceStable(traces, func(i, j int) bool { return traces[i].TimeStamp < traces[j].TimeStamp })
+	var end time.Duration
+	if len(traces) > 0 {
+		end = traces[len(traces)-1].TimeStamp
+	}
+	interval := o.Interval
+	if interval <= 0 {
+		interval = max(end/100, 1)
+	}
+	if n := (end+interval-1)/interval + 1; n > MaxFrames {
+		return nil, fmt.Errorf("%d frames at %v, more than %d: raise the interval", n, interval, MaxFrames)
+	}
+
+	type placement struct {
+		pos     board.Position
+		symbol  rune
+		arrived int
+	}
+	at := make(map[int]placement)
+	var frames []Frame
+	next := 0
+	for t := time.Duration(0); ; t += interval {
+		for ; next < len(traces) && traces[next].TimeStamp <= t; next++ {
+			e := traces[next]
+			at[e.Id] = placement{pos: e.Position, symbol: e.Symbol, arrived: next + 1}
+		}
+		fr := Frame{Time: t, Cells: make([][]Cell, h.Height)}
+		top := make([][]int, h.Height)
+		for y := range fr.Cells {
+			fr.Cells[y] = make([]Cell, h.Width)
+			top[y] = make([]int, h.Width)
+		}
+		for id, p := range at {
+			x, y := p.pos.X, p.pos.Y
+			if x < 0 || x >= h.Width || y < 0 || y >= h.Height {
+				continue // hidden
+			}
+			if h.Kind(id) == trace.KindTrap {
+				fr.Cells[y][x].Trap = true
+			}
+			if p.arrived > top[y][x] {
+				top[y][x] = p.arrived
+				fr.Cells[y][x].Symbol = p.symbol
+			}
+		}
+		frames = append(frames, fr)
+		if next == len(traces) {
+			return frames, nil
+		}
+	}
+}
+
+// delay is how long frame i stays on screen, the last one for a second
+func (o Options) delay(frames []Frame, i int) time.Duration {
+	speed := o.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	var d time.Duration
+	if i+1 < len(frames) {
+		d = time.Duration(float64(frames[i+1].Time-frames[i].Time) / speed)
+	} else {
+		d = time.Second
+	}
+	// browsers show shorter GIF delays as 100ms
+	return max(d, 20*time.Millisecond)
+}
+
+func (o Options) cell() int {
+	if o.Cell <= 0 {
+		return DefaultCell
+	}
+	return o.Cell
+}
+
+// kind tells how a symbol is coloured, as in distrav
+func kind(sym rune) string {
+	switch {
+	case sym == '#':
+		return "trap"
+	case sym == '*':
+		return "trapped"
+	case sym >= '0' && sym <= '9':
+		return "wild"
+	case sym >= 'a' && sym <= 'z':
+		return "deadlock"
+	}
+	return "traveler"
+}
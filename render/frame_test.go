@@ -0,0 +1,124 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// run is a trace of zad4 on a 3x2 board: trap -1 on (2,1), traveler A
+// and traveler B, printed one after the other; B steps on the trap and is
+// caught, then A takes the cell B left
+const run = "0 -1 2 1 #\n" +
+	"0.1 0 0 0 A\n0.3 0 1 0 A\n" +
+	"0.2 1 1 0 B\n0.25 1 2 1 *\n" +
+	"-1 2 3 2 TRAPS=-1..-1;\n"
+
+func parse(t *testing.T, in string) *trace.File {
+	t.Helper()
+	f, err := trace.Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// draw returns the rows of fr, a cell its symbol or '.' when empty, in
+// brackets when a trap is set on it
+func draw(fr Frame) []string {
+	rows := make([]string, len(fr.Cells))
+	for y, row := range fr.Cells {
+		var b strings.Builder
+		for _, c := range row {
+			sym := "."
+			if c.Symbol != 0 {
+				sym = string(c.Symbol)
+			}
+			if c.Trap {
+				sym = "[" + sym + "]"
+			}
+			b.WriteString(sym)
+		}
+		rows[y] = b.String()
+	}
+	return rows
+}
+
+func TestFrames(t *testing.T) {
+	frames, err := Frames(parse(t, run), Options{Interval: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		time time.Duration
+		rows []string
+	}{
+		{0, []string{"...", "..[#]"}},
+		{100 * time.Millisecond, []string{"A..", "..[#]"}},
+		{200 * time.Millisecond, []string{"AB.", "..[#]"}},
+		{300 * time.Millisecond, []string{".A.", "..[*]"}},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("%d frames, want %d", len(frames), len(want))
+	}
+	for i, w := range want {
+		if frames[i].Time != w.time {
+			t.Errorf("frame %d at %v, want %v", i, frames[i].Time, w.time)
+		}
+		if got := draw(frames[i]); strings.Join(got, "/") != strings.Join(w.rows, "/") {
+			t.Errorf("frame %d = %q, want %q", i, got, w.rows)
+		}
+	}
+}
+
+func TestFramesInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		interval time.Duration
+		frames   int // 0 for an error
+	}{
+		{"a hundredth of the run", run, 0, 101},
+		{"the last frame after the last trace", run, 70 * time.Millisecond, 6},
+		{"an empty trace", "-1 2 3 2\n", 0, 1},
+		{"too many frames", run, time.Microsecond, 0},
+		{"no board", "0.1 0 0 0 A\n-1 1 0 0\n", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames, err := Frames(parse(t, tt.in), Options{Interval: tt.interval})
+			switch {
+			case tt.frames == 0 && err == nil:
+				t.Errorf("%d frames, want an error", len(frames))
+			case tt.frames > 0 && err != nil:
+				t.Error(err)
+			case len(frames) != tt.frames:
+				t.Errorf("%d frames, want %d", len(frames), tt.frames)
+			}
+		})
+	}
+}
+
+func TestDelay(t *testing.T) {
+	frames := []Frame{{Time: 0}, {Time: 100 * time.Millisecond}, {Time: 200 * time.Millisecond}, {Time: 205 * time.Millisecond}}
+	tests := []struct {
+		speed float64
+		frame int
+		want  time.Duration
+	}{
+		{0, 0, 100 * time.Millisecond},
+		{1, 1, 100 * time.Millisecond},
+		{2, 0, 50 * time.Millisecond},
+		{0.5, 0, 200 * time.Millisecond},
+		{1, 2, 20 * time.Millisecond}, // 5ms, the shortest delay browsers keep
+		{1, 3, time.Second},           // the last frame
+		{2, 3, time.Second},
+	}
+	for _, tt := range tests {
+		if got := (Options{Speed: tt.speed}).delay(frames, tt.frame); got != tt.want {
+			t.Errorf("speed %v: frame %d stays %v, want %v", tt.speed, tt.frame, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Indexes in palette
+const (
+	colBackground = iota
+	colGrid
+	colTrapCell
+	colText
+	colTraveler
+	colWild
+	colTrap
+	colTrapped
+	colDeadlock
+)
+
+var palette = color.Palette{
+	colBackground: color.RGBA{0x11, 0x11, 0x11, 0xff},
+	colGrid:       color.RGBA{0x40, 0x40, 0x40, 0xff},
+	colTrapCell:   color.RGBA{0x3a, 0x10, 0x10, 0xff},
+	colText:       color.RGBA{0xaa, 0xaa, 0xaa, 0xff},
+	colTraveler:   color.RGBA{0xff, 0xff, 0xff, 0xff},
+	colWild:       color.RGBA{0xe6, 0xc2, 0x29, 0xff},
+	colTrap:       color.RGBA{0xe0, 0x40, 0x40, 0xff},
+	colTrapped:    color.RGBA{0xd0, 0x50, 0xd0, 0xff},
+	colDeadlock:   color.RGBA{0x40, 0xd0, 0xe0, 0xff},
+}
+
+var kindColor = map[string]uint8{
+	"traveler": colTraveler,
+	"wild":     colWild,
+	"trap":     colTrap,
+	"trapped":  colTrapped,
+	"deadlock": colDeadlock,
+}
+
+// layout places the caption, the row labels and the grid of a frame
+type layout struct {
+	cell   int // side of a cell, with its grid line
+	scale  int // of the glyphs in the cells
+	text   int // scale of the caption and the labels
+	left   int // x of the grid
+	top    int // y of the grid
+	width  int
+	height int
+}
+
+func newLayout(h *trace.Header, o Options) layout {
+	l := layout{cell: o.cell()}
+	l.scale = max(1, (l.cell-2)/(glyphHeight+2))
+	l.text = max(1, l.scale/2)
+	l.top = (glyphHeight + 4) * l.text
+	longest := 0
+	for _, label := range h.Rows {
+		longest = max(longest, len(label))
+	}
+	if longest > 0 {
+		l.left = (longest*(glyphWidth+1) + 2) * l.text
+	}
+	l.width = l.left + h.Width*l.cell + 1
+	l.height = l.top + h.Height*l.cell + 1
+	return l
+}
+
+// Image draws a frame of a trace with header h
+func Image(h *trace.Header, fr Frame, o Options) *image.Paletted {
+	l := newLayout(h, o)
+	img := image.NewPaletted(image.Rect(0, 0, l.width, l.height), palette)
+	fill(img, img.Rect, colBackground)
+	text(img, 0, 2*l.text, l.text, fmt.Sprintf("TIME = %.6f", fr.Time.Seconds()), colText)
+	for y, label := range h.Rows {
+		if y < h.Height {
+			text(img, 0, l.top+y*l.cell+(l.cell-glyphHeight*l.text)/2, l.text, label, colText)
+		}
+	}
+	for y, row := range fr.Cells {
+		for x, c := range row {
+			x0, y0 := l.left+x*l.cell, l.top+y*l.cell
+			r := image.Rect(x0, y0, x0+l.cell+1, y0+l.cell+1)
+			fill(img, r, colGrid)
+			bg := uint8(colBackground)
+			if c.Trap {
+				bg = colTrapCell
+			}
+			fill(img, r.Inset(1), bg)
+			if c.Symbol != 0 {
+				dx := (l.cell + 1 - glyphWidth*l.scale) / 2
+				dy := (l.cell + 1 - glyphHeight*l.scale) / 2
+				text(img, x0+dx, y0+dy, l.scale, string(c.Symbol), kindColor[kind(c.Symbol)])
+			}
+		}
+	}
+	return img
+}
+
+func fill(img *image.Paletted, r image.Rectangle, c uint8) {
+	r = r.Intersect(img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, c)
+		}
+	}
+}
+
+// text draws s with its top left corner at (x, y), every pixel of the
+// font a scale x scale square
+func text(img *image.Paletted, x, y, scale int, s string, c uint8) {
+	for _, r := range s {
+		g := glyph(r)
+		for gy, row := range g {
+			for gx, p := range row {
+				if p == '#' {
+					px, py := x+gx*scale, y+gy*scale
+					fill(img, image.Rect(px, py, px+scale, py+scale), c)
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
+
+func images(f *trace.File, o Options) ([]*image.Paletted, []time.Duration, error) {
+	frames, err := Frames(f, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	imgs := make([]*image.Paletted, len(frames))
+	delays := make([]time.Duration, len(frames))
+	for i, fr := range frames {
+		imgs[i] = Image(&f.Header, fr, o)
+		delays[i] = o.delay(frames, i)
+	}
+	return imgs, delays, nil
+}
+
+// WriteGIF writes the run as an animated GIF, looping forever
+func WriteGIF(w io.Writer, f *trace.File, o Options) error {
+	imgs, delays, err := images(f, o)
+	if err != nil {
+		return err
+	}
+	anim := &gif.GIF{Image: imgs, Delay: make([]int, len(delays))}
+	for i, d := range delays {
+		anim.Delay[i] = int(d / (10 * time.Millisecond)) // hundredths of a second
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// WriteAPNG writes the run as an animated PNG, looping forever; it takes
+// the chunks of every frame encoded by image/png, which has no APNG
+func WriteAPNG(w io.Writer, f *trace.File, o Options) error {
+	imgs, delays, err := images(f, o)
+	if err != nil {
+		return err
+	}
+	a := &apngWriter{w: w}
+	a.write([]byte("\x89PNG\r\n\x1a\n"))
+	for i, img := range imgs {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		chunks, err := pngChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			for _, c := range chunks {
+				if c.typ == "IHDR" {
+					a.chunk("IHDR", c.data)
+					a.chunk("acTL", be32(uint32(len(imgs)), 0))
+				}
+			}
+			for _, c := range chunks {
+				if c.typ == "PLTE" || c.typ == "tRNS" {
+					a.chunk(c.typ, c.data)
+				}
+			}
+		}
+		b := img.Bounds()
+		ms := min(delays[i].Milliseconds(), 0xffff)
+		a.chunk("fcTL", append(be32(a.seq(), uint32(b.Dx()), uint32(b.Dy()), 0, 0),
+			byte(ms>>8), byte(ms), 0x03, 0xe8, // delay in ms/1000
+			0, 0)) // no disposal, no blending
+		for _, c := range chunks {
+			if c.typ != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				a.chunk("IDAT", c.data)
+			} else {
+				a.chunk("fdAT", append(be32(a.seq()), c.data...))
+			}
+		}
+	}
+	a.chunk("IEND", nil)
+	return a.err
+}
+
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// pngChunks splits a PNG file into its chunks
+func pngChunks(b []byte) ([]pngChunk, error) {
+	const sig = 8
+	if len(b) < sig {
+		return nil, fmt.Errorf("short PNG")
+	}
+	var chunks []pngChunk
+	for b = b[sig:]; len(b) >= 12; {
+		n := int(binary.BigEndian.Uint32(b))
+		if len(b) < 12+n {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{typ: string(b[4:8]), data: b[8 : 8+n]})
+		b = b[12+n:]
+	}
+	return chunks, nil
+}
+
+type apngWriter struct {
+	w   io.Writer
+	n   uint32 // sequence number of the next fcTL or fdAT
+	err error
+}
+
+func (a *apngWriter) seq() uint32 {
+	a.n++
+	return a.n - 1
+}
+
+func (a *apngWriter) write(b []byte) {
+	if a.err == nil {
+		_, a.err = a.w.Write(b)
+	}
+}
+
+func (a *apngWriter) chunk(typ string, data []byte) {
+	body := append([]byte(typ), data...)
+	a.write(be32(uint32(len(data))))
+	a.write(body)
+	a.write(be32(crc32.ChecksumIEEE(body)))
+}
+
+func be32(vs ...uint32) []byte {
+	b := make([]byte, 0, 4*len(vs))
+	for _, v := range vs {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return b
+}
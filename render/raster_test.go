@@ -0,0 +1,117 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/gif"
+	"image/png"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWriteAPNG(t *testing.T) {
+	f := parse(t, run)
+	o := Options{Interval: 100 * time.Millisecond, Cell: 10}
+	var buf bytes.Buffer
+	if err := WriteAPNG(&buf, f, o); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	chunks, err := pngChunks(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pngChunks skips the CRCs, check them on the file itself
+	for rest := b[8:]; len(rest) >= 12; {
+		n := int(binary.BigEndian.Uint32(rest))
+		if crc := binary.BigEndian.Uint32(rest[8+n:]); crc != crc32.ChecksumIEEE(rest[4:8+n]) {
+			t.Errorf("%s chunk: bad CRC", rest[4:8])
+		}
+		rest = rest[12+n:]
+	}
+
+	var types []string
+	for _, c := range chunks {
+		if len(types) == 0 || types[len(types)-1] != c.typ {
+			types = append(types, c.typ)
+		}
+	}
+	// 4 frames, the first one the default image
+	want := []string{"IHDR", "acTL", "PLTE", "fcTL", "IDAT", "fcTL", "fdAT", "fcTL", "fdAT", "fcTL", "fdAT", "IEND"}
+	if !slices.Equal(types, want) {
+		t.Fatalf("chunks %v, want %v", types, want)
+	}
+	if got := binary.BigEndian.Uint32(chunks[1].data); got != 4 {
+		t.Errorf("acTL: %d frames, want 4", got)
+	}
+	if plays := binary.BigEndian.Uint32(chunks[1].data[4:]); plays != 0 {
+		t.Errorf("acTL: %d plays, want 0 to loop forever", plays)
+	}
+
+	frames, err := Frames(f, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := Image(&f.Header, frames[0], o)
+	seq, frame := uint32(0), 0
+	for _, c := range chunks {
+		switch c.typ {
+		case "fcTL":
+			if got := binary.BigEndian.Uint32(c.data); got != seq {
+				t.Errorf("frame %d: fcTL sequence number %d, want %d", frame, got, seq)
+			}
+			if w, h := binary.BigEndian.Uint32(c.data[4:]), binary.BigEndian.Uint32(c.data[8:]); int(w) != img.Rect.Dx() || int(h) != img.Rect.Dy() {
+				t.Errorf("frame %d: %dx%d, want %v", frame, w, h, img.Rect.Size())
+			}
+			num, den := binary.BigEndian.Uint16(c.data[20:]), binary.BigEndian.Uint16(c.data[22:])
+			if got, want := time.Duration(num)*time.Second/time.Duration(den), o.delay(frames, frame); got != want {
+				t.Errorf("frame %d: delay %v, want %v", frame, got, want)
+			}
+			seq++
+			frame++
+		case "fdAT":
+			if got := binary.BigEndian.Uint32(c.data); got != seq {
+				t.Errorf("frame %d: fdAT sequence number %d, want %d", frame-1, got, seq)
+			}
+			seq++
+		}
+	}
+
+	// a plain PNG decoder shows the first frame
+	first, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Bounds() != img.Rect {
+		t.Fatalf("default image %v, want %v", first.Bounds(), img.Rect)
+	}
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if first.At(x, y) != img.At(x, y) {
+				t.Fatalf("default image differs from frame 0 at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	f := parse(t, run)
+	o := Options{Interval: 100 * time.Millisecond, Speed: 2, Cell: 10}
+	var buf bytes.Buffer
+	if err := WriteGIF(&buf, f, o); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hundredths of a second: 50ms at twice the speed, a second at the end
+	if want := []int{5, 5, 5, 100}; !slices.Equal(anim.Delay, want) {
+		t.Errorf("delays %v, want %v", anim.Delay, want)
+	}
+	if anim.LoopCount != 0 {
+		t.Errorf("loop count %d, want 0 to loop forever", anim.LoopCount)
+	}
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// WriteSVG writes a frame of a trace with header h as SVG, laid out as
+// Image draws it
+func WriteSVG(w io.Writer, h *trace.Header, fr Frame, o Options) error {
+	l := newLayout(h, o)
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-weight="bold">`+"\n",
+		l.width, l.height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(palette[colBackground]))
+	textSize := (glyphHeight + 2) * l.text
+	fmt.Fprintf(b, `<text x="0" y="%d" font-size="%d" fill="%s">TIME = %.6f</text>`+"\n",
+		2*l.text+textSize*3/4, textSize, hex(palette[colText]), fr.Time.Seconds())
+	for y, label := range h.Rows {
+		if y < h.Height {
+			fmt.Fprintf(b, `<text x="0" y="%d" font-size="%d" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+				l.top+y*l.cell+l.cell/2, textSize, hex(palette[colText]), html.EscapeString(label))
+		}
+	}
+	for y, row := range fr.Cells {
+		for x, c := range row {
+			x0, y0 := l.left+x*l.cell, l.top+y*l.cell
+			bg := palette[colBackground]
+			if c.Trap {
+				bg = palette[colTrapCell]
+			}
+			fmt.Fprintf(b, `<rect x="%d.5" y="%d.5" width="%d" height="%d" fill="%s" stroke="%s"/>`+"\n",
+				x0, y0, l.cell, l.cell, hex(bg), hex(palette[colGrid]))
+			if c.Symbol != 0 {
+				fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+					x0+l.cell/2, y0+l.cell/2, (glyphHeight+2)*l.scale, hex(palette[kindColor[kind(c.Symbol)]]),
+					html.EscapeString(string(c.Symbol)))
+			}
+		}
+	}
+	fmt.Fprintln(b, "</svg>")
+	return b.Flush()
+}
+
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
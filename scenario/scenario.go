@@ -0,0 +1,424 @@
+// Package scenario loads declarative descriptions of a whole travelers
+// simulation run from JSON or YAML files.
+//
+// A scenario overrides the board.Config of a program and lists the
+// travelers, traps and wild tenant spawns explicitly, so that a run
+// starts from exactly the same setup every time:
+//
+//	name: diagonal-deadlock
+//	config:
+//	  width: 4
+//	  height: 4
+//	  min_delay: 10ms
+//	travelers:
+//	  - {symbol: A, start: {x: 0, y: 0}, steps: 20, move: down}
+//	  - {symbol: B, start: {x: 1, y: 1}, steps: 20, move: left}
+//	traps:
+//	  - {x: 3, y: 0}
+//	wild:
+//	  - {spawn: 100ms, start: {x: 2, y: 2}, symbol: "7", lifespan: 1s}
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+// Duration is a time.Duration written as "250ms", "1.5s" etc.
+type Duration time.Duration
+
+func (d *Duration) set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"10ms\": %w", err)
+	}
+	return d.set(s)
+}
+
+// UnmarshalYAML parses a duration string
+func (d *Duration) UnmarshalYAML(n *yaml.Node) error {
+	var s string
+	if err := n.Decode(&s); err != nil {
+		return err
+	}
+	return d.set(s)
+}
+
+// Move is the movement rule of a traveler
+type Move string
+
+const (
+	MoveRandom Move = "random" // a random step every time
+	MoveUp     Move = "up"     // always the same direction
+	MoveDown   Move = "down"
+	MoveLeft   Move = "left"
+	MoveRight  Move = "right"
+)
+
+// Direction returns the fixed direction of the rule, ok is false for MoveRandom
+func (m Move) Direction() (d board.Direction, ok bool) {
+	switch m {
+	case MoveUp:
+		return board.Up, true
+	case MoveDown:
+		return board.Down, true
+	case MoveLeft:
+		return board.Left, true
+	case MoveRight:
+		return board.Right, true
+	}
+	return 0, false
+}
+
+// Position is a cell of the board
+type Position struct {
+	X int `json:"x" yaml:"x"`
+	Y int `json:"y" yaml:"y"`
+}
+
+// Board converts the position to the board package type
+func (p Position) Board() board.Position {
+	return board.Position{X: p.X, Y: p.Y}
+}
+
+// Config overrides the parameters of board.Config; missing fields keep
+// the values given on the command line
+type Config struct {
+	Width           *int      `json:"width" yaml:"width"`
+	Height          *int      `json:"height" yaml:"height"`
+	MinSteps        *int      `json:"min_steps" yaml:"min_steps"`
+	MaxSteps        *int      `json:"max_steps" yaml:"max_steps"`
+	MinDelay        *Duration `json:"min_delay" yaml:"min_delay"`
+	MaxDelay        *Duration `json:"max_delay" yaml:"max_delay"`
+	WildMinLifespan *Duration `json:"wild_min_lifespan" yaml:"wild_min_lifespan"`
+	WildMaxLifespan *Duration `json:"wild_max_lifespan" yaml:"wild_max_lifespan"`
+	TrapBlockTime   *Duration `json:"trap_block" yaml:"trap_block"`
+}
+
+// Traveler describes one traveler; missing fields are chosen at random
+// the way the program normally does
+type Traveler struct {
+	Symbol string    `json:"symbol" yaml:"symbol"`
+	Start  *Position `json:"start" yaml:"start"`
+	Steps  *int      `json:"steps" yaml:"steps"`
+	Move   Move      `json:"move" yaml:"move"`
+}
+
+// Wild describes one wild tenant spawn
+type Wild struct {
+	Spawn    Duration  `json:"spawn" yaml:"spawn"` // time since the start of the run
+	Start    *Position `json:"start" yaml:"start"`
+	Symbol   string    `json:"symbol" yaml:"symbol"`
+	Lifespan *Duration `json:"lifespan" yaml:"lifespan"`
+}
+
+// Scenario is a whole simulation run
+type Scenario struct {
+	Name      string     `json:"name" yaml:"name"`
+	Config    Config     `json:"config" yaml:"config"`
+	Travelers []Traveler `json:"travelers" yaml:"travelers"`
+	Traps     []Position `json:"traps" yaml:"traps"`
+	Wild      []Wild     `json:"wild" yaml:"wild"`
+}
+
+// Load reads a scenario file, the format is chosen by its extension
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scenario{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(s)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(s)
+	default:
+		return nil, fmt.Errorf("%s: unknown scenario format, use .json, .yaml or .yml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Apply overrides cfg with the scenario; the numbers of travelers, traps
+// and wild tenants become the lengths of the corresponding lists, an
+// empty list leaves the number of the flags, the entities then placed at
+// random
+func (s *Scenario) Apply(cfg *board.Config) {
+	c := s.Config
+	setInt := func(dst *int, src *int) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setDuration := func(dst *time.Duration, src *Duration) {
+		if src != nil {
+			*dst = time.Duration(*src)
+		}
+	}
+	setInt(&cfg.BoardWidth, c.Width)
+	setInt(&cfg.BoardHeight, c.Height)
+	setInt(&cfg.MinSteps, c.MinSteps)
+	setInt(&cfg.MaxSteps, c.MaxSteps)
+	setDuration(&cfg.MinDelay, c.MinDelay)
+	setDuration(&cfg.MaxDelay, c.MaxDelay)
+	setDuration(&cfg.WildMinLifespan, c.WildMinLifespan)
+	setDuration(&cfg.WildMaxLifespan, c.WildMaxLifespan)
+	setDuration(&cfg.TrapBlockTime, c.TrapBlockTime)
+	if len(s.Travelers) > 0 {
+		cfg.NrOfTravelers = len(s.Travelers)
+	}
+	if len(s.Traps) > 0 {
+		cfg.NrOfTraps = len(s.Traps)
+	}
+	if len(s.Wild) > 0 {
+		cfg.NrOfWildSpawns = len(s.Wild)
+	}
+}
+
+// Validate checks the scenario against the configuration it was applied to;
+// exclusive programs also need distinct cells for every entity. A nil
+// scenario is valid.
+func (s *Scenario) Validate(cfg *board.Config, exclusive bool) error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	onBoard := func(p Position) bool {
+		return p.X >= 0 && p.X < cfg.BoardWidth && p.Y >= 0 && p.Y < cfg.BoardHeight
+	}
+	taken := make(map[Position]string)
+	take := func(p Position, who string) {
+		if other, ok := taken[p]; ok && exclusive {
+			errs = append(errs, fmt.Errorf("%s and %s both start at (%d,%d)", other, who, p.X, p.Y))
+		}
+		taken[p] = who
+	}
+
+	for _, p := range s.Traps {
+		check(onBoard(p), "trap (%d,%d) is outside the board", p.X, p.Y)
+		take(p, fmt.Sprintf("trap (%d,%d)", p.X, p.Y))
+	}
+	symbols := make(map[string]bool)
+	for i, t := range s.Travelers {
+		who := fmt.Sprintf("traveler %d", i)
+		if t.Symbol != "" {
+			check(len(t.Symbol) == 1 && t.Symbol[0] >= 'A' && t.Symbol[0] <= 'Z',
+				"%s: symbol must be a single capital letter, got %q", who, t.Symbol)
+			check(!symbols[t.Symbol], "%s: symbol %q is used twice", who, t.Symbol)
+			symbols[t.Symbol] = true
+		}
+		if t.Start != nil {
+			check(onBoard(*t.Start), "%s: start (%d,%d) is outside the board", who, t.Start.X, t.Start.Y)
+			take(*t.Start, who)
+		}
+		if t.Steps != nil {
+			check(*t.Steps >= 0 && *t.Steps <= cfg.MaxSteps,
+				"%s: steps must be in 0..%d, got %d", who, cfg.MaxSteps, *t.Steps)
+		}
+		if _, fixed := t.Move.Direction(); t.Move != "" && t.Move != MoveRandom && !fixed {
+			errs = append(errs, fmt.Errorf("%s: unknown move %q", who, t.Move))
+		}
+	}
+	for i, w := range s.Wild {
+		who := fmt.Sprintf("wild tenant %d", i)
+		check(w.Spawn >= 0, "%s: spawn time must not be negative", who)
+		if w.Symbol != "" {
+			check(len(w.Symbol) == 1 && w.Symbol[0] >= '0' && w.Symbol[0] <= '9',
+				"%s: symbol must be a single digit, got %q", who, w.Symbol)
+		}
+		if w.Start != nil {
+			check(onBoard(*w.Start), "%s: start (%d,%d) is outside the board", who, w.Start.X, w.Start.Y)
+			check(!slices.Contains(s.Traps, *w.Start), "%s: start (%d,%d) is a trap", who, w.Start.X, w.Start.Y)
+			for j, t := range s.Travelers {
+				// a traveler that finishes there keeps the cell to the end
+				check(t.Start == nil || *t.Start != *w.Start,
+					"%s: start (%d,%d) is the start of traveler %d", who, w.Start.X, w.Start.Y, j)
+			}
+		}
+		if w.Lifespan != nil {
+			check(*w.Lifespan > 0, "%s: lifespan must be positive", who)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Traveler returns the description of traveler id, or nil if the
+// scenario does not list it
+func (s *Scenario) Traveler(id int) *Traveler {
+	if s == nil || id >= len(s.Travelers) {
+		return nil
+	}
+	return &s.Travelers[id]
+}
+
+// SymbolOr returns the traveler's symbol, or def when none is given
+func (t *Traveler) SymbolOr(def rune) rune {
+	if t == nil || t.Symbol == "" {
+		return def
+	}
+	return rune(t.Symbol[0])
+}
+
+// StartOr returns the traveler's start position, or calls def when none is given
+func (t *Traveler) StartOr(def func() board.Position) board.Position {
+	if t == nil || t.Start == nil {
+		return def()
+	}
+	return t.Start.Board()
+}
+
+// StepsOr returns the traveler's number of steps, or calls def when none is given
+func (t *Traveler) StepsOr(def func() int) int {
+	if t == nil || t.Steps == nil {
+		return def()
+	}
+	return *t.Steps
+}
+
+// Direction returns the traveler's fixed direction, ok is false when it
+// moves at random or the scenario leaves it to the program
+func (t *Traveler) Direction() (d board.Direction, ok bool) {
+	if t == nil {
+		return 0, false
+	}
+	return t.Move.Direction()
+}
+
+// Fixed tells whether the scenario chooses the movement rule at all
+func (t *Traveler) Fixed() bool {
+	return t != nil && t.Move != ""
+}
+
+// Setup loads the scenario at path (if any) into cfg and validates both;
+// it returns a nil scenario when path is empty
+func Setup(cfg *board.Config, path string, exclusive bool) (*Scenario, error) {
+	var s *Scenario
+	if path != "" {
+		var err error
+		if s, err = Load(path); err != nil {
+			return nil, err
+		}
+		s.Apply(cfg)
+	}
+	validate := cfg.Validate
+	if exclusive {
+		validate = cfg.ValidateExclusive
+	}
+	if err := validate(); err != nil {
+		return nil, err
+	}
+	if s != nil {
+		if err := s.Validate(cfg, exclusive); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+// WildSpawn returns the description of the i-th wild tenant spawn, or nil if
+// the scenario does not list it
+func (s *Scenario) WildSpawn(i int) *Wild {
+	if s == nil || i >= len(s.Wild) {
+		return nil
+	}
+	return &s.Wild[i]
+}
+
+// TrapPositions returns the trap coordinates, or nil if the scenario does
+// not place traps
+func (s *Scenario) TrapPositions() []board.Position {
+	if s == nil {
+		return nil
+	}
+	var ps []board.Position
+	for _, p := range s.Traps {
+		ps = append(ps, p.Board())
+	}
+	return ps
+}
+
+// Starts returns the start positions the scenario fixes for its
+// travelers and wild tenants, which random traps have to leave free
+func (s *Scenario) Starts() []board.Position {
+	if s == nil {
+		return nil
+	}
+	var ps []board.Position
+	for _, t := range s.Travelers {
+		if t.Start != nil {
+			ps = append(ps, t.Start.Board())
+		}
+	}
+	for _, w := range s.Wild {
+		if w.Start != nil {
+			ps = append(ps, w.Start.Board())
+		}
+	}
+	return ps
+}
+
+// StartOr returns the tenant's start position, or calls def when none is given
+func (w *Wild) StartOr(def func() board.Position) board.Position {
+	if w == nil || w.Start == nil {
+		return def()
+	}
+	return w.Start.Board()
+}
+
+// SymbolOr returns the tenant's symbol, or calls def when none is given
+func (w *Wild) SymbolOr(def func() rune) rune {
+	if w == nil || w.Symbol == "" {
+		return def()
+	}
+	return rune(w.Symbol[0])
+}
+
+// LifespanOr returns the tenant's lifespan, or calls def when none is given
+func (w *Wild) LifespanOr(def func() time.Duration) time.Duration {
+	if w == nil || w.Lifespan == nil {
+		return def()
+	}
+	return time.Duration(*w.Lifespan)
+}
+
+// SpawnTime returns the time since the start of the run at which the
+// tenant appears, zero when the scenario does not list it
+func (w *Wild) SpawnTime() time.Duration {
+	if w == nil {
+		return 0
+	}
+	return time.Duration(w.Spawn)
+}
@@ -0,0 +1,48 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+func TestValidateWildStart(t *testing.T) {
+	at := func(x, y int) *Position { return &Position{x, y} }
+	tests := []struct {
+		name string
+		s    Scenario
+		want string // in the error, none if empty
+	}{
+		{"a cell of its own", Scenario{
+			Travelers: []Traveler{{Start: at(1, 1)}},
+			Wild:      []Wild{{Start: at(2, 2)}},
+		}, ""},
+		{"two tenants one after the other", Scenario{
+			Wild: []Wild{{Start: at(2, 2)}, {Spawn: Duration(1e9), Start: at(2, 2)}},
+		}, ""},
+		{"a traveler's start", Scenario{
+			Travelers: []Traveler{{Start: at(0, 0)}, {Start: at(1, 1)}},
+			Wild:      []Wild{{Start: at(1, 1)}},
+		}, "wild tenant 0: start (1,1) is the start of traveler 1"},
+		{"a trap", Scenario{
+			Traps: []Position{{3, 3}},
+			Wild:  []Wild{{Start: at(3, 3)}},
+		}, "wild tenant 0: start (3,3) is a trap"},
+		{"outside the board", Scenario{
+			Wild: []Wild{{Start: at(5, 0)}},
+		}, "wild tenant 0: start (5,0) is outside the board"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &board.Config{BoardWidth: 5, BoardHeight: 5, MaxSteps: 10}
+			err := tt.s.Validate(cfg, true)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Validate: %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
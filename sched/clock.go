@@ -0,0 +1,81 @@
+// Package sched is the source of time of the simulations.
+//
+// Every goroutine that sleeps, waits for a timeout or busy-waits is a
+// participant started with Clock.Go. The Real clock simply forwards to the
+// time package. The Virtual clock keeps a logical time and lets a single
+// participant run at a time; when every participant sleeps it advances the
+// logical time to the earliest wake-up, so a run with a fixed seed produces
+// the same interleaving, and the same trace, on every execution.
+package sched
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// Clock is the time and scheduling abstraction used by the simulations
+type Clock interface {
+	// Now returns the time since the start of the run
+	Now() time.Duration
+	// Sleep pauses the calling participant
+	Sleep(d time.Duration)
+	// Yield is one iteration of a busy wait
+	Yield()
+	// After returns a timer that fires once d has elapsed; a participant
+	// waiting on it in a select must call Park before the select and must
+	// Stop it before blocking anywhere else
+	After(d time.Duration) *Timer
+	// Park tells the clock that the participant is about to block on
+	// channels, Unpark that it was woken by another participant
+	Park()
+	Unpark()
+	// NewMutex returns a lock whose waiting participants the clock can see
+	NewMutex() Mutex
+	// Go starts a participant
+	Go(f func())
+	// Wait returns when every participant has returned
+	Wait()
+}
+
+// Mutex is a lock that can also be acquired with a timeout
+type Mutex interface {
+	Lock()
+	Unlock()
+	// TryLockFor waits at most d for the lock and reports whether it got it
+	TryLockFor(d time.Duration) bool
+}
+
+// Timer fires once on C
+type Timer struct {
+	C    <-chan struct{}
+	stop func() bool
+}
+
+// Stop cancels the timer, it reports false if the timer already fired
+func (t *Timer) Stop() bool {
+	return t.stop()
+}
+
+// Kinds of clocks accepted by New
+const (
+	KindReal    = "real"
+	KindVirtual = "virtual"
+)
+
+// New returns a clock of the given kind, starting now
+func New(kind string) (Clock, error) {
+	switch kind {
+	case KindReal:
+		return NewReal(), nil
+	case KindVirtual:
+		return NewVirtual(), nil
+	}
+	return nil, fmt.Errorf("unknown clock %q, use %q or %q", kind, KindReal, KindVirtual)
+}
+
+// Register defines the -clock flag on fs
+func Register(fs *flag.FlagSet) *string {
+	return fs.String("clock", KindReal,
+		"real: wall-clock time; virtual: simulated time with a deterministic schedule")
+}
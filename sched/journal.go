@@ -0,0 +1,234 @@
+package sched
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Journal records the order in which the participants of a run passed
+// their scheduling decisions: cell operations, lock grants and timeouts.
+// Replaying the journal forces the same order on a later run, so an
+// interleaving seen once with the real clock can be reproduced at will.
+//
+// A nil *Journal is valid and runs every decision freely.
+type Journal struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// recording
+	out    *bufio.Writer
+	closer io.Closer
+
+	// replaying
+	entries []entry
+	own     map[int][]int // indices of the entries of each participant
+	done    map[int]int   // entries of each participant already replayed
+	next    int           // index of the entry whose turn it is
+	err     error         // set once the run no longer follows the journal
+}
+
+type entry struct {
+	who     int
+	outcome bool
+	event   string
+}
+
+// NewRecorder returns a journal writing the decisions of the run to w;
+// Close flushes it
+func NewRecorder(w io.Writer) *Journal {
+	j := &Journal{out: bufio.NewWriter(w)}
+	if c, ok := w.(io.Closer); ok {
+		j.closer = c
+	}
+	j.cond = sync.NewCond(&j.mu)
+	return j
+}
+
+// NewReplayer reads a journal written by a recorder and returns a journal
+// that makes every participant wait for its recorded turn
+func NewReplayer(r io.Reader) (*Journal, error) {
+	j := &Journal{own: make(map[int][]int), done: make(map[int]int)}
+	j.cond = sync.NewCond(&j.mu)
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.SplitN(sc.Text(), " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("journal line %d: want \"<who> ok|no <event>\", got %q", line, sc.Text())
+		}
+		who, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("journal line %d: participant: %w", line, err)
+		}
+		if fields[1] != "ok" && fields[1] != "no" {
+			return nil, fmt.Errorf("journal line %d: outcome must be ok or no, got %q", line, fields[1])
+		}
+		j.own[who] = append(j.own[who], len(j.entries))
+		j.entries = append(j.entries, entry{who: who, outcome: fields[1] == "ok", event: fields[2]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Do runs op, an operation that does not block, as the next decision of
+// participant who
+func (j *Journal) Do(who int, event string, op func()) {
+	if j == nil {
+		op()
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.out != nil {
+		// op runs under the lock, so the journal order is the order in
+		// which the operations took effect
+		op()
+		j.write(who, true, event)
+		return
+	}
+	if !j.await(who, event) {
+		op()
+		return
+	}
+	op()
+	j.advance(who)
+}
+
+// Decide makes a decision of participant who whose outcome depends on
+// timing, like acquiring a lock with a timeout. When recording, decide
+// runs freely and its outcome is logged; when replaying, the participant
+// waits for its turn and apply makes the recorded outcome happen.
+func (j *Journal) Decide(who int, event string, decide func() bool, apply func(bool)) bool {
+	if j == nil {
+		return decide()
+	}
+	if j.out != nil {
+		ok := decide()
+		j.mu.Lock()
+		j.write(who, ok, event)
+		j.mu.Unlock()
+		return ok
+	}
+	j.mu.Lock()
+	if !j.await(who, event) {
+		j.mu.Unlock()
+		return decide()
+	}
+	defer j.mu.Unlock()
+	ok := j.entries[j.next].outcome
+	apply(ok)
+	j.advance(who)
+	return ok
+}
+
+// write logs one entry; j.mu must be held
+func (j *Journal) write(who int, ok bool, event string) {
+	outcome := "no"
+	if ok {
+		outcome = "ok"
+	}
+	fmt.Fprintf(j.out, "%d %s %s\n", who, outcome, event)
+}
+
+// await waits until the next entry of who is the next entry of the
+// journal; it reports false once the run diverged. j.mu must be held.
+func (j *Journal) await(who int, event string) bool {
+	if j.err != nil {
+		return false
+	}
+	n := j.done[who]
+	if n >= len(j.own[who]) {
+		j.diverge(fmt.Errorf("participant %d did %q after the end of its journal", who, event))
+		return false
+	}
+	idx := j.own[who][n]
+	if e := j.entries[idx]; e.event != event {
+		j.diverge(fmt.Errorf("participant %d did %q where the journal says %q (entry %d)", who, event, e.event, idx+1))
+		return false
+	}
+	for j.next != idx && j.err == nil {
+		j.cond.Wait()
+	}
+	return j.err == nil
+}
+
+func (j *Journal) advance(who int) {
+	j.done[who]++
+	j.next++
+	j.cond.Broadcast()
+}
+
+// diverge releases every waiting participant; j.mu must be held
+func (j *Journal) diverge(err error) {
+	j.err = fmt.Errorf("sched: replay diverged: %w", err)
+	j.cond.Broadcast()
+}
+
+// Close flushes a recorded journal; for a replayed one it reports whether
+// the run stopped following the journal
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.out != nil {
+		err := j.out.Flush()
+		if j.closer != nil {
+			err = errors.Join(err, j.closer.Close())
+		}
+		return err
+	}
+	if j.err == nil && j.next < len(j.entries) {
+		return fmt.Errorf("sched: replay stopped after %d of %d journal entries", j.next, len(j.entries))
+	}
+	return j.err
+}
+
+// JournalFlags are the -record and -replay command line flags
+type JournalFlags struct {
+	Record string
+	Replay string
+}
+
+// RegisterJournal defines the -record and -replay flags on fs
+func RegisterJournal(fs *flag.FlagSet) *JournalFlags {
+	f := &JournalFlags{}
+	fs.StringVar(&f.Record, "record", "", "write the scheduling decisions of the run to this file")
+	fs.StringVar(&f.Replay, "replay", "", "force the scheduling decisions recorded in this file")
+	return f
+}
+
+// Open returns the journal asked for on the command line, nil if none.
+// Replaying needs the real clock: the virtual one is deterministic anyway.
+func (f *JournalFlags) Open(clockKind string) (*Journal, error) {
+	switch {
+	case f.Record != "" && f.Replay != "":
+		return nil, errors.New("-record and -replay are mutually exclusive")
+	case f.Record != "":
+		out, err := os.Create(f.Record)
+		if err != nil {
+			return nil, err
+		}
+		return NewRecorder(out), nil
+	case f.Replay != "":
+		if clockKind != KindReal {
+			return nil, fmt.Errorf("-replay needs the %s clock", KindReal)
+		}
+		in, err := os.Open(f.Replay)
+		if err != nil {
+			return nil, err
+		}
+		defer in.Close()
+		return NewReplayer(in)
+	}
+	return nil, nil
+}
@@ -0,0 +1,65 @@
+package sched
+
+import (
+	"sync"
+	"time"
+)
+
+// Real is the wall clock
+type Real struct {
+	start time.Time
+	wg    sync.WaitGroup
+}
+
+// NewReal returns a wall clock starting now
+func NewReal() *Real {
+	return &Real{start: time.Now()}
+}
+
+func (c *Real) Now() time.Duration { return time.Since(c.start) }
+
+func (c *Real) Sleep(d time.Duration) { time.Sleep(d) }
+
+// Yield sleeps a microsecond, as the original busy waits did
+func (c *Real) Yield() { time.Sleep(time.Microsecond) }
+
+func (c *Real) After(d time.Duration) *Timer {
+	ch := make(chan struct{}, 1)
+	t := time.AfterFunc(d, func() { ch <- struct{}{} })
+	return &Timer{C: ch, stop: t.Stop}
+}
+
+func (c *Real) Park()   {}
+func (c *Real) Unpark() {}
+
+func (c *Real) NewMutex() Mutex {
+	return make(realMutex, 1)
+}
+
+func (c *Real) Go(f func()) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		f()
+	}()
+}
+
+func (c *Real) Wait() { c.wg.Wait() }
+
+// realMutex is a channel semaphore, so that giving up on a timeout leaves
+// nothing behind
+type realMutex chan struct{}
+
+func (m realMutex) Lock()   { m <- struct{}{} }
+func (m realMutex) Unlock() { <-m }
+
+func (m realMutex) TryLockFor(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case m <- struct{}{}:
+		return true
+	case <-t.C:
+		return false
+	}
+}
@@ -0,0 +1,346 @@
+package sched
+
+import (
+	"container/heap"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultSpinCost is the simulated time one iteration of a busy wait takes
+const DefaultSpinCost = 100 * time.Microsecond
+
+// ErrStalled tells that every participant of a virtual clock is blocked
+// with no wake-up pending, so the run can never go on
+var ErrStalled = errors.New("sched: every participant of the virtual clock is blocked")
+
+// Virtual is a simulated clock. Only one participant runs at a time; the
+// others are parked in Sleep, Lock, TryLockFor or behind Park. When the
+// running participant parks, the earliest pending wake-up is dispatched,
+// ties being broken by the order in which the wake-ups were requested.
+//
+// When the participants stall, see ErrStalled, the clock wakes all of
+// them for good: the next call of each to a method that would park it
+// unwinds it with a panic, which Go recovers, so that Wait returns.
+type Virtual struct {
+	SpinCost time.Duration // time charged by Yield
+
+	mu      sync.Mutex
+	now     time.Duration
+	seq     uint64
+	queue   wakeups
+	running int // participants not parked, at most one outside of channel hand-offs
+	live    int // participants that have not returned yet
+	started bool
+	done    chan struct{}
+	stalled chan struct{}
+	err     error           // ErrStalled once the participants are unwinding
+	mutexes []*virtualMutex // for waking their waiters on a stall
+}
+
+// unwind is the panic value that stops a participant of a stalled clock
+type unwind struct{ err error }
+
+// NewVirtual returns a simulated clock at time zero
+func NewVirtual() *Virtual {
+	return &Virtual{SpinCost: DefaultSpinCost, done: make(chan struct{}), stalled: make(chan struct{})}
+}
+
+// Stalled returns a channel closed once every participant is blocked
+// with no wake-up pending, see ErrStalled. The participants then unwind
+// and Wait returns once all of them have.
+func (v *Virtual) Stalled() <-chan struct{} {
+	return v.stalled
+}
+
+// wakeup is a pending event; fire runs under the clock's lock and makes
+// exactly one participant running
+type wakeup struct {
+	at        time.Duration
+	seq       uint64
+	fire      func()
+	cancelled bool
+	index     int
+}
+
+type wakeups []*wakeup
+
+func (q wakeups) Len() int { return len(q) }
+func (q wakeups) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+func (q wakeups) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+func (q *wakeups) Push(x any) {
+	w := x.(*wakeup)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+func (q *wakeups) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	w.index = -1 // no longer pending
+	*q = old[:len(old)-1]
+	return w
+}
+
+// schedule queues fire at time at; v.mu must be held
+func (v *Virtual) schedule(at time.Duration, fire func()) *wakeup {
+	v.seq++
+	w := &wakeup{at: at, seq: v.seq, fire: fire}
+	heap.Push(&v.queue, w)
+	return w
+}
+
+// wakeOn returns a fire function that resumes the participant blocked on ch
+func (v *Virtual) wakeOn(ch chan struct{}) func() {
+	return func() {
+		v.running++
+		close(ch)
+	}
+}
+
+// park marks the caller as not running and dispatches the next wake-up
+// if nobody runs any more; v.mu must be held. On a stalled clock it
+// releases v.mu and unwinds the caller instead.
+func (v *Virtual) park() {
+	if v.err != nil {
+		err := v.err
+		v.mu.Unlock()
+		panic(unwind{err})
+	}
+	v.running--
+	v.dispatch()
+}
+
+// await blocks the caller until ch is closed by its wake-up and unwinds
+// it if that came from a stall
+func (v *Virtual) await(ch chan struct{}) {
+	<-ch
+	v.mu.Lock()
+	err := v.err
+	v.mu.Unlock()
+	if err != nil {
+		panic(unwind{err})
+	}
+}
+
+func (v *Virtual) dispatch() {
+	if !v.started || v.running > 0 || v.err != nil {
+		return
+	}
+	for v.queue.Len() > 0 {
+		w := heap.Pop(&v.queue).(*wakeup)
+		if w.cancelled {
+			continue
+		}
+		if w.at > v.now {
+			v.now = w.at
+		}
+		w.fire()
+		return
+	}
+	if v.live > 0 {
+		v.stall()
+	}
+}
+
+// stall wakes every parked participant for good, see Stalled; v.mu must
+// be held
+func (v *Virtual) stall() {
+	v.err = ErrStalled
+	close(v.stalled)
+	for _, m := range v.mutexes {
+		for _, w := range m.waiters {
+			if w.timeout != nil {
+				w.timeout.cancelled = true
+			}
+			w.resume()
+		}
+		m.waiters = nil
+	}
+	for v.queue.Len() > 0 {
+		if w := heap.Pop(&v.queue).(*wakeup); !w.cancelled {
+			w.fire()
+		}
+	}
+}
+
+func (v *Virtual) Now() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+func (v *Virtual) Sleep(d time.Duration) {
+	ch := make(chan struct{})
+	v.mu.Lock()
+	v.schedule(v.now+max(d, 0), v.wakeOn(ch))
+	v.park()
+	v.mu.Unlock()
+	v.await(ch)
+}
+
+// Yield advances the simulated time by SpinCost
+func (v *Virtual) Yield() {
+	v.Sleep(v.SpinCost)
+}
+
+func (v *Virtual) After(d time.Duration) *Timer {
+	ch := make(chan struct{}, 1)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	w := v.schedule(v.now+max(d, 0), func() {
+		v.running++
+		ch <- struct{}{}
+	})
+	return &Timer{C: ch, stop: func() bool {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		if w.cancelled || w.index < 0 {
+			return false
+		}
+		w.cancelled = true
+		return true
+	}}
+}
+
+func (v *Virtual) Park() {
+	v.mu.Lock()
+	v.park()
+	v.mu.Unlock()
+}
+
+func (v *Virtual) Unpark() {
+	v.mu.Lock()
+	if err := v.err; err != nil {
+		// woken by the stall rather than by a participant
+		v.mu.Unlock()
+		panic(unwind{err})
+	}
+	v.running++
+	v.mu.Unlock()
+}
+
+// Go queues f to start at the current simulated time
+func (v *Virtual) Go(f func()) {
+	ch := make(chan struct{})
+	v.mu.Lock()
+	v.live++
+	v.schedule(v.now, v.wakeOn(ch))
+	v.mu.Unlock()
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				if _, ok := p.(unwind); !ok {
+					panic(p)
+				}
+			}
+			v.mu.Lock()
+			v.live--
+			if v.live == 0 {
+				close(v.done)
+			}
+			if v.err == nil {
+				v.running--
+				v.dispatch()
+			}
+			v.mu.Unlock()
+		}()
+		v.await(ch)
+		f()
+	}()
+}
+
+// Wait starts dispatching the participants and returns when all of them
+// have returned, or unwound after a stall, see Stalled
+func (v *Virtual) Wait() {
+	v.mu.Lock()
+	v.started = true
+	if v.live == 0 {
+		v.mu.Unlock()
+		return
+	}
+	v.dispatch()
+	v.mu.Unlock()
+	<-v.done
+}
+
+func (v *Virtual) NewMutex() Mutex {
+	m := &virtualMutex{v: v}
+	v.mu.Lock()
+	v.mutexes = append(v.mutexes, m)
+	v.mu.Unlock()
+	return m
+}
+
+// virtualMutex hands the lock over to its waiters in FIFO order; the new
+// owner is queued at the current time, so it runs after the releasing
+// participant parks
+type virtualMutex struct {
+	v       *Virtual
+	locked  bool
+	waiters []*lockWaiter
+}
+
+type lockWaiter struct {
+	granted bool
+	resume  func()
+	timeout *wakeup
+}
+
+func (m *virtualMutex) Lock() {
+	m.acquire(-1)
+}
+
+func (m *virtualMutex) TryLockFor(d time.Duration) bool {
+	return m.acquire(d)
+}
+
+// acquire waits for the lock, forever if d is negative
+func (m *virtualMutex) acquire(d time.Duration) bool {
+	v := m.v
+	v.mu.Lock()
+	if !m.locked {
+		m.locked = true
+		v.mu.Unlock()
+		return true
+	}
+	ch := make(chan struct{})
+	w := &lockWaiter{resume: v.wakeOn(ch)}
+	m.waiters = append(m.waiters, w)
+	if d >= 0 {
+		w.timeout = v.schedule(v.now+d, func() {
+			m.waiters = slices.DeleteFunc(m.waiters, func(other *lockWaiter) bool { return other == w })
+			w.resume()
+		})
+	}
+	v.park()
+	v.mu.Unlock()
+	v.await(ch)
+	return w.granted
+}
+
+func (m *virtualMutex) Unlock() {
+	v := m.v
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(m.waiters) == 0 {
+		m.locked = false
+		return
+	}
+	// the lock stays locked, it now belongs to the first waiter
+	w := m.waiters[0]
+	m.waiters = m.waiters[1:]
+	w.granted = true
+	if w.timeout != nil {
+		w.timeout.cancelled = true
+	}
+	v.schedule(v.now, w.resume)
+}
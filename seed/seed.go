@@ -0,0 +1,78 @@
+// Package seed derives every random number generator of a run from a
+// single master seed, so that a run can be repeated with the same choices.
+package seed
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// Master is the seed of a whole run
+type Master int64
+
+// Derive returns the seed of the i-th generator of the given stream,
+// e.g. Derive("traveler", 3); it depends only on the master seed and its
+// arguments
+func (m Master) Derive(stream string, i int) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(stream))
+	x := uint64(m) ^ h.Sum64()
+	x += uint64(i+1) * 0x9e3779b97f4a7c15
+	// splitmix64 finalizer
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return int64(x ^ (x >> 31))
+}
+
+// Rand returns the i-th generator of the given stream
+func (m Master) Rand(stream string, i int) *rand.Rand {
+	return rand.New(rand.NewSource(m.Derive(stream, i)))
+}
+
+// String is the value of the SEED=... label of the trace header
+func (m Master) String() string {
+	return strconv.FormatInt(int64(m), 10)
+}
+
+// Flag is the -seed command-line flag; when it is not given a master
+// seed is taken from the clock
+type Flag struct {
+	master Master
+	set    bool
+}
+
+// Register defines -seed on fs
+func Register(fs *flag.FlagSet) *Flag {
+	f := &Flag{}
+	fs.Var(f, "seed", "master seed of the run (default: taken from the clock)")
+	return f
+}
+
+func (f *Flag) String() string {
+	if f == nil || !f.set {
+		return ""
+	}
+	return strconv.FormatInt(int64(f.master), 10)
+}
+
+func (f *Flag) Set(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("seed must be an integer: %w", err)
+	}
+	f.master, f.set = Master(v), true
+	return nil
+}
+
+// Master returns the seed given on the command line or, the first time
+// it is called without one, a fresh seed from the clock
+func (f *Flag) Master() Master {
+	if !f.set {
+		f.master, f.set = Master(time.Now().UnixNano()), true
+	}
+	return f.master
+}
@@ -0,0 +1,85 @@
+package sim
+
+import (
+	"sync"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+)
+
+// bus delivers the traces of a live run one at a time, in time stamp
+// order, as soon as the participants take them. The time stamp is taken
+// under the lock of the queue, so the queue is in time order whatever
+// the clock and however the participants interleave.
+type bus struct {
+	clock   sched.Clock
+	deliver func(t board.Trace)
+
+	mu     sync.Mutex
+	queue  []board.Trace
+	closed bool
+	ready  chan struct{} // signalled when there is something to deliver
+	done   chan struct{} // closed once the last trace is delivered
+}
+
+func newBus(clock sched.Clock, deliver func(t board.Trace)) *bus {
+	return &bus{clock: clock, deliver: deliver, ready: make(chan struct{}, 1), done: make(chan struct{})}
+}
+
+// start starts delivering, the traces taken before are kept until then
+func (b *bus) start() {
+	go func() {
+		defer close(b.done)
+		for range b.ready {
+			b.mu.Lock()
+			queue, closed := b.queue, b.closed
+			b.queue = nil
+			b.mu.Unlock()
+			for _, t := range queue {
+				b.deliver(t)
+			}
+			if closed {
+				return
+			}
+		}
+	}()
+}
+
+// stamp stamps t with the current time and queues it
+func (b *bus) stamp(t board.Trace) board.Trace {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t.TimeStamp = b.clock.Now()
+	b.push(t)
+	return t
+}
+
+// publish queues a trace stamped by the caller, which must not be older
+// than the traces queued before
+func (b *bus) publish(t board.Trace) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.push(t)
+}
+
+// push queues t; b.mu must be held
+func (b *bus) push(t board.Trace) {
+	b.queue = append(b.queue, t)
+	b.signal()
+}
+
+func (b *bus) signal() {
+	select {
+	case b.ready <- struct{}{}:
+	default:
+	}
+}
+
+// close delivers the traces still queued and stops the bus
+func (b *bus) close() {
+	b.mu.Lock()
+	b.closed = true
+	b.signal()
+	b.mu.Unlock()
+	<-b.done
+}
@@ -0,0 +1,122 @@
+package sim_test
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/lista1/go3/travelers3"
+	"github.com/TrollYuck/PW_INA_2025/lista2/zad4go/traps"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/seed"
+	"github.com/TrollYuck/PW_INA_2025/sim"
+)
+
+// small shrinks the board and the delays of cfg so that a run on the
+// real clock takes a fraction of a second
+func small(cfg *board.Config) {
+	cfg.NrOfTravelers, cfg.BoardWidth, cfg.BoardHeight = 6, 6, 6
+	cfg.MinSteps, cfg.MaxSteps = 10, 20
+	cfg.MinDelay, cfg.MaxDelay = time.Millisecond, 5*time.Millisecond
+	cfg.NrOfWildSpawns, cfg.WildMinLifespan, cfg.WildMaxLifespan = 4, 20*time.Millisecond, 60*time.Millisecond
+	cfg.NrOfTraps, cfg.TrapBlockTime = 4, 20*time.Millisecond
+}
+
+// the models with a journal, each returning a small one using j
+var models = []struct {
+	name string
+	new  func(j *sched.Journal) sim.Model
+}{
+	{"go3", func(j *sched.Journal) sim.Model {
+		m := travelers3.New()
+		small(&m.Config)
+		m.Journal = j
+		return m
+	}},
+	{"zad4", func(j *sched.Journal) sim.Model {
+		m := traps.New()
+		small(&m.Config)
+		m.Journal = j
+		return m
+	}},
+}
+
+// TestVirtualDeterministic runs every model twice on the virtual clock
+// with the same seed: the printed traces must be the same to the byte
+func TestVirtualDeterministic(t *testing.T) {
+	for _, tt := range models {
+		t.Run(tt.name, func(t *testing.T) {
+			for s := seed.Master(1); s <= 3; s++ {
+				var out [2]bytes.Buffer
+				for i := range out {
+					sm := sim.Simulation{Model: tt.new(nil), Seed: s, Clock: sched.KindVirtual, Output: &out[i]}
+					if _, err := sm.Run(context.Background()); err != nil {
+						t.Fatalf("seed %v: %v", s, err)
+					}
+				}
+				if !bytes.Equal(out[0].Bytes(), out[1].Bytes()) {
+					t.Errorf("seed %v: the runs differ:\n%s\n---\n%s", s, &out[0], &out[1])
+				}
+			}
+		})
+	}
+}
+
+// steps returns the traces of every entity without their time stamps,
+// which the real clock never repeats
+func steps(res *sim.Result) map[int][]board.Trace {
+	byId := make(map[int][]board.Trace)
+	for _, tr := range res.Traces {
+		tr.TimeStamp = 0
+		byId[tr.Id] = append(byId[tr.Id], tr)
+	}
+	return byId
+}
+
+// TestRecordReplay records a run of every model on the real clock, as
+// -record does, and replays it, as -replay does: every entity must go
+// through the same traces again
+func TestRecordReplay(t *testing.T) {
+	for _, tt := range models {
+		t.Run(tt.name, func(t *testing.T) {
+			for s := seed.Master(1); s <= 3; s++ {
+				var journal bytes.Buffer
+				rec := sched.NewRecorder(&journal)
+				sm := sim.Simulation{Model: tt.new(rec), Seed: s}
+				recorded, err := sm.Run(context.Background())
+				if err == nil {
+					err = rec.Close()
+				}
+				if err != nil {
+					t.Fatalf("seed %v, recording: %v", s, err)
+				}
+
+				rep, err := sched.NewReplayer(&journal)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sm.Model = tt.new(rep)
+				replayed, err := sm.Run(context.Background())
+				if err == nil {
+					err = rep.Close()
+				}
+				if err != nil {
+					t.Fatalf("seed %v, replaying: %v", s, err)
+				}
+
+				want, got := steps(recorded), steps(replayed)
+				for id, w := range want {
+					if !slices.Equal(got[id], w) {
+						t.Errorf("seed %v: entity %d went\n%v\nwhen recorded, replayed\n%v", s, id, w, got[id])
+					}
+				}
+				if len(got) != len(want) {
+					t.Errorf("seed %v: %d entities replayed, %d recorded", s, len(got), len(want))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package sim
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Result is what happened in a run
+type Result struct {
+	Header     trace.Header  // the final parameter line
+	Traces     []board.Trace // in the order they were printed
+	Outcomes   []Outcome     // one per entity, in ID order
+	Deadlocked []int         // travelers that gave up waiting, in lowercase
+	Cycles     [][]int       // the waiting cycles behind them, when the program finds them
+	Traps      []TrapHit     // entities caught in a trap, in time order
+	MaxTicket  int64         // highest ticket of the bakery, 0 for the other programs
+}
+
+// Outcome is where an entity ended the run
+type Outcome struct {
+	Id    int
+	Kind  trace.Kind
+	Last  board.Trace   // its last trace, e.g. a Deadlock, an Expired or a Finished one
+	Moves int           // traces at a new position: steps, or state changes of a process
+	Start time.Duration // time stamp of its first trace
+}
+
+// TrapHit is an entity caught in a trap
+type TrapHit struct {
+	Trap      int // ID of the trap, 0 if the trap did not report the catch
+	Victim    int
+	Kind      trace.Kind // of the victim
+	Position  board.Position
+	TimeStamp time.Duration
+}
+
+// File returns the trace as if read from the output of the program
+func (res *Result) File() *trace.File {
+	return &trace.File{Header: res.Header, Traces: res.Traces}
+}
+
+func newResult(h trace.Header, traces []board.Trace) *Result {
+	res := &Result{Header: h, Traces: traces}
+	res.MaxTicket, _ = h.Param("MAX_TICKET")
+	res.Cycles = parseCycles(h.Params["DEADLOCKS"])
+
+	byId := make(map[int]*Outcome)
+	var caught []int // indexes in res.Traps of the victims not yet paired with a trap
+	for _, t := range traces {
+		o, ok := byId[t.Id]
+		if !ok {
+			o = &Outcome{Id: t.Id, Kind: h.Kind(t.Id), Start: t.TimeStamp}
+			byId[t.Id] = o
+		} else if t.Position != o.Last.Position {
+			o.Moves++
+		}
+		o.Last = t
+
+		switch {
+		case t.Event == board.Deadlock:
+			res.Deadlocked = append(res.Deadlocked, t.Id)
+		case t.Event == board.Trapped:
+			caught = append(caught, len(res.Traps))
+			res.Traps = append(res.Traps, TrapHit{Victim: t.Id, Kind: o.Kind, Position: t.Position, TimeStamp: t.TimeStamp})
+		case o.Kind == trace.KindTrap && ok:
+			// a trap reports again once it released its catch, which
+			// printed its traces before; a traveler is stamped at the
+			// cell it stepped from, next to the trap
+			for i, k := range caught {
+				if hit := &res.Traps[k]; near(&h, hit.Position, t.Position) {
+					hit.Trap, hit.Position = t.Id, t.Position
+					caught = append(caught[:i], caught[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	for _, o := range byId {
+		res.Outcomes = append(res.Outcomes, *o)
+	}
+	sort.Slice(res.Outcomes, func(i, j int) bool { return res.Outcomes[i].Id < res.Outcomes[j].Id })
+	sort.Ints(res.Deadlocked)
+	sort.SliceStable(res.Traps, func(i, j int) bool { return res.Traps[i].TimeStamp < res.Traps[j].TimeStamp })
+	return res
+}
+
+// parseCycles reads the DEADLOCKS parameter, "0>2>4,1>3"
+func parseCycles(s string) [][]int {
+	if s == "" {
+		return nil
+	}
+	var cycles [][]int
+	for _, c := range strings.Split(s, ",") {
+		var cycle []int
+		for _, f := range strings.Split(c, ">") {
+			id, err := strconv.Atoi(f)
+			if err != nil {
+				return cycles
+			}
+			cycle = append(cycle, id)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// near reports whether p is q or one of its neighbours on the torus
+func near(h *trace.Header, p, q board.Position) bool {
+	dx := (p.X - q.X + h.Width) % h.Width
+	dy := (p.Y - q.Y + h.Height) % h.Height
+	return dx == 0 && (dy <= 1 || dy == h.Height-1) || dy == 0 && (dx <= 1 || dx == h.Width-1)
+}
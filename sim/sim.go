@@ -0,0 +1,295 @@
+// Package sim runs the simulations from Go code. A Simulation puts the
+// part specific to one program, its Model, together with what every run
+// shares: the clock, the master seed, the trace output and a sink for
+// the traces. Run returns the whole trace and what became of every
+// entity, so that tests and benchmarks can drive many runs without
+// starting a program and parsing what it printed:
+//
+//	m := travelers3.New()
+//	s := sim.Simulation{Model: m, Seed: 7, Clock: sched.KindVirtual}
+//	res, err := s.Run(ctx)
+//
+// The programs themselves are thin wrappers setting a Simulation up from
+// their command line.
+//
+// A program prints the traces of a traveler or process once it finishes,
+// so the trace comes out in batches. A live Simulation prints every trace
+// as soon as it is taken instead, in time stamp order, for following a
+// run with tail -f or drawing it as it goes; the participants take their
+// traces with Run.Stamp for that.
+package sim
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/dashboard"
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/seed"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Model is a program: its parameters and how a single run goes
+type Model interface {
+	// Run sets the run up, calls r.Begin, starts the participants on
+	// r.Clock, waits for them and calls r.End. A Model must allow several
+	// runs at once.
+	Run(r *Run) error
+}
+
+// Sink receives every trace of a run, in the order they are printed
+type Sink func(t board.Trace)
+
+// Simulation describes the runs of a model
+type Simulation struct {
+	Model  Model
+	Seed   seed.Master
+	Clock  string    // sched.KindReal or sched.KindVirtual, real if empty
+	Output io.Writer // receives the trace as the programs print it, may be nil
+	Format string    // format of Output, trace.FormatText if empty
+	Sink   Sink      // may be nil
+	Live   bool      // print every trace at once, in time order
+
+	// Monitor receives the trace in JSON Lines too, whatever the Format,
+	// like a dashboard.Server; it may be nil
+	Monitor io.Writer
+}
+
+// Run carries out a run and returns its result. Cancelling ctx stops the
+// travelers and processes before their next step, the wild tenants live
+// to the end of their lifespan; the result then holds the trace so far
+// and the error is ctx.Err().
+//
+// A run on the virtual clock whose participants all block for good
+// returns sched.ErrStalled. The clock unwinds the participants and the
+// run's context is cancelled with ErrStalled as the cause, which stops
+// the cell servers; Run returns once the model did.
+func (s *Simulation) Run(ctx context.Context) (*Result, error) {
+	kind := s.Clock
+	if kind == "" {
+		kind = sched.KindReal
+	}
+	clk, err := sched.New(kind)
+	if err != nil {
+		return nil, err
+	}
+	var stalled <-chan struct{} // nil, never closed, for the real clock
+	if v, ok := clk.(*sched.Virtual); ok {
+		stalled = v.Stalled()
+	}
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	r := &Run{Context: runCtx, Clock: clk, Seed: s.Seed, sim: s}
+	if s.Live {
+		r.bus = newBus(clk, r.print)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Model.Run(r) }()
+	select {
+	case err = <-done:
+	case <-stalled:
+		cancel(sched.ErrStalled)
+		<-done
+		return nil, sched.ErrStalled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !r.ended {
+		return nil, errors.New("sim: the model did not end its trace")
+	}
+	return newResult(r.header, r.traces), ctx.Err()
+}
+
+// Run is a single run of a Simulation, as its Model sees it
+type Run struct {
+	Context context.Context // done when the participants should stop
+	Clock   sched.Clock
+	Seed    seed.Master
+
+	sim    *Simulation
+	bus    *bus // nil unless live
+	out    *trace.Writer
+	mon    *trace.Writer // nil without a Monitor
+	header trace.Header
+	traces []board.Trace
+	ended  bool
+}
+
+// Stopped reports whether the run was cancelled; the participants check
+// it before every step
+func (r *Run) Stopped() bool {
+	return r.Context.Err() != nil
+}
+
+// Begin starts the trace of the run described by h, adding the SEED
+// parameter; it is called once, before any trace is printed
+func (r *Run) Begin(h trace.Header) error {
+	h.Params = maps.Clone(h.Params)
+	if h.Params == nil {
+		h.Params = make(map[string]string)
+	}
+	h.Params["SEED"] = r.Seed.String()
+	format := r.sim.Format
+	if format == "" {
+		format = trace.FormatText
+	}
+	out := r.sim.Output
+	if out == nil {
+		out = io.Discard
+	}
+	var err error
+	if r.out, err = trace.NewWriter(out, format, h); err != nil {
+		return err
+	}
+	if r.sim.Monitor != nil {
+		if r.mon, err = trace.NewWriter(r.sim.Monitor, trace.FormatJSONL, h); err != nil {
+			return err
+		}
+	}
+	r.header = h
+	if r.bus != nil {
+		r.bus.start()
+	}
+	return nil
+}
+
+// Stamp returns t stamped with the current time. Every trace is taken
+// with it, so that a live run prints it at once.
+func (r *Run) Stamp(t board.Trace) board.Trace {
+	if r.bus != nil {
+		return r.bus.stamp(t)
+	}
+	t.TimeStamp = r.Clock.Now()
+	return t
+}
+
+// Publish is Stamp for a trace with a time stamp of its own, like the
+// traps set at time 0; it must not be older than the traces before
+func (r *Run) Publish(t board.Trace) {
+	if r.bus != nil {
+		r.bus.publish(t)
+	}
+}
+
+// Print prints a single trace. The traces of a run are printed by one
+// goroutine at a time, usually a printer of NewPrinter. A live run
+// printed them already and ignores it.
+func (r *Run) Print(t board.Trace) {
+	if r.bus == nil {
+		r.print(t)
+	}
+}
+
+func (r *Run) print(t board.Trace) {
+	r.traces = append(r.traces, t)
+	if r.sim.Sink != nil {
+		r.sim.Sink(t)
+	}
+	if r.sim.Output != nil {
+		r.out.Format(r.sim.Output, t)
+	}
+	if r.mon != nil {
+		r.mon.Format(r.sim.Monitor, t)
+	}
+}
+
+// NewPrinter returns a printer of the traces of the run, see
+// board.NewPrinter
+func (r *Run) NewPrinter(buffer int) *board.Printer {
+	return board.NewPrinter(r.sim.Output, func(_ io.Writer, t board.Trace) { r.Print(t) }, buffer)
+}
+
+// End finishes the trace once every trace is printed; params are the
+// parameters only known at the end, like MAX_TICKET
+func (r *Run) End(params map[string]string) error {
+	if r.bus != nil {
+		r.bus.close()
+	}
+	maps.Copy(r.header.Params, params)
+	r.ended = true
+	if r.mon != nil {
+		if err := r.mon.End(r.header); err != nil {
+			return err
+		}
+	}
+	return r.out.End(r.header)
+}
+
+// Flags are the command-line flags of every program: -seed, -clock,
+// -format, -live and -serve
+type Flags struct {
+	Seed   *seed.Flag
+	Clock  *string
+	Format *string
+	Live   *bool
+	Serve  *string
+}
+
+// RegisterFlags defines the flags on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	return &Flags{
+		Seed:   seed.Register(fs),
+		Clock:  sched.Register(fs),
+		Format: trace.RegisterFormat(fs),
+		Live:   fs.Bool("live", false, "print every trace as soon as it is taken, in time order, instead of by traveler"),
+		Serve:  fs.String("serve", "", "show the run live in the browser at this address, like :8080"),
+	}
+}
+
+// Simulation returns the simulation of m asked for on the command line,
+// printing to the standard output
+func (f *Flags) Simulation(m Model) *Simulation {
+	return &Simulation{Model: m, Seed: f.Seed.Master(), Clock: *f.Clock, Output: os.Stdout, Format: *f.Format, Live: *f.Live}
+}
+
+// Run runs m as asked for on the command line. With -serve it waits for
+// a browser to open the dashboard, runs m live and keeps serving the
+// dashboard until interrupted; interrupting the run stops it.
+func (f *Flags) Run(m Model) (*Result, error) {
+	s := f.Simulation(m)
+	if *f.Serve == "" {
+		return s.Run(context.Background())
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ln, err := net.Listen("tcp", *f.Serve)
+	if err != nil {
+		return nil, err
+	}
+	d := dashboard.New()
+	srv := &http.Server{Handler: d}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	host := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		host = "localhost"
+	}
+	fmt.Fprintf(os.Stderr, "open http://%s/ to start the run\n", net.JoinHostPort(host, strconv.Itoa(addr.Port)))
+	select {
+	case <-d.Connected():
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	s.Live, s.Monitor = true, d
+	res, err := s.Run(ctx)
+	d.Finish()
+	if err != nil {
+		return res, err
+	}
+	fmt.Fprintln(os.Stderr, "run finished, interrupt to stop serving")
+	<-ctx.Done()
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/sched"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// crossing takes two locks in opposite orders, the classic deadlock
+type crossing struct{}
+
+func (crossing) Run(r *Run) error {
+	if err := r.Begin(trace.Header{Travelers: 2, Width: 2, Height: 1}); err != nil {
+		return err
+	}
+	a, b := r.Clock.NewMutex(), r.Clock.NewMutex()
+	take := func(first, second sched.Mutex) {
+		first.Lock()
+		r.Clock.Sleep(time.Millisecond)
+		second.Lock()
+	}
+	r.Clock.Go(func() { take(a, b) })
+	r.Clock.Go(func() { take(b, a) })
+	r.Clock.Wait()
+	return r.End(nil)
+}
+
+func TestRunStalled(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for range 50 {
+		s := Simulation{Model: crossing{}, Seed: 1, Clock: sched.KindVirtual}
+		res, err := s.Run(context.Background())
+		if !errors.Is(err, sched.ErrStalled) {
+			t.Fatalf("Run = %v, %v, want ErrStalled", res, err)
+		}
+	}
+	// the unwound participants may still be on their way out
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines after the stalled runs, %d before", n, before)
+	}
+}
@@ -0,0 +1,95 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// chromeEvent is one entry of the Chrome trace-event format, as read by
+// chrome://tracing and Perfetto
+type chromeEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"` // microseconds
+	Dur  *float64       `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// WriteChrome converts a lista3 trace into Chrome trace-event JSON: one
+// track per process with a slice for every state it went through, and a
+// counter of the processes in each state, which makes two processes in
+// CRITICAL_SECTION at once stand out. name is the title of the run.
+func WriteChrome(w io.Writer, f *File, name string) error {
+	h := &f.Header
+	if len(h.Rows) == 0 {
+		return fmt.Errorf("trace has no row labels, it is not a lista3 trace")
+	}
+	const pid = 1
+	events := []chromeEvent{
+		{Name: "process_name", Ph: "M", Pid: pid, Args: map[string]any{"name": name}},
+	}
+
+	intervals := f.Intervals()
+	named := make(map[int]bool)
+	type change struct {
+		at    time.Duration
+		row   int
+		delta int
+	}
+	var changes []change
+	for _, iv := range intervals {
+		if !named[iv.Id] {
+			named[iv.Id] = true
+			events = append(events,
+				chromeEvent{Name: "thread_name", Ph: "M", Pid: pid, Tid: iv.Id,
+					Args: map[string]any{"name": fmt.Sprintf("%c (process %d)", iv.Symbol, iv.Id)}},
+				chromeEvent{Name: "thread_sort_index", Ph: "M", Pid: pid, Tid: iv.Id,
+					Args: map[string]any{"sort_index": iv.Id}})
+		}
+		row := iv.Position.Y
+		if row < 0 || row >= len(h.Rows) {
+			return fmt.Errorf("process %d at %v: row %d has no label", iv.Id, iv.Start, row)
+		}
+		dur := micros(iv.End - iv.Start)
+		events = append(events, chromeEvent{
+			Name: h.Rows[row], Cat: "state", Ph: "X",
+			Ts: micros(iv.Start), Dur: &dur, Pid: pid, Tid: iv.Id,
+		})
+		changes = append(changes, change{iv.Start, row, +1}, change{iv.End, row, -1})
+	}
+
+	// counters, leaving a state before entering the next one at the same time
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].at != changes[j].at {
+			return changes[i].at < changes[j].at
+		}
+		return changes[i].delta < changes[j].delta
+	})
+	counts := make([]int, len(h.Rows))
+	for i, c := range changes {
+		counts[c.row] += c.delta
+		if i+1 < len(changes) && changes[i+1].at == c.at {
+			continue
+		}
+		args := make(map[string]any, len(counts))
+		for row, n := range counts {
+			args[h.Rows[row]] = n
+		}
+		events = append(events, chromeEvent{Name: "processes", Ph: "C", Ts: micros(c.at), Pid: pid, Args: args})
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeEvent `json:"traceEvents"`
+		DisplayTimeUnit string        `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
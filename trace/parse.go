@@ -0,0 +1,189 @@
+package trace
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+// SyntaxError describes a malformed line
+type SyntaxError struct {
+	Line int    // 1-based line number
+	Text string // the offending line
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("trace line %d: %s: %q", e.Line, e.Msg, e.Text)
+}
+
+// ErrNoHeader is returned by Parse for a trace without a parameter line
+var ErrNoHeader = errors.New("trace: no \"-1 N W H\" parameter line")
+
+// Reader reads a trace line by line
+type Reader struct {
+	sc     *bufio.Scanner
+	line   int
+	header *Header
+}
+
+// NewReader returns a reader of the trace in r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{sc: bufio.NewScanner(r)}
+}
+
+// Header returns the parameter line if it was already read, nil otherwise
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Next returns the next trace, skipping blank lines and reading the
+// parameter line on the way; it returns io.EOF at the end of the input
+func (r *Reader) Next() (board.Trace, error) {
+	for r.sc.Scan() {
+		r.line++
+		text := r.sc.Text()
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "-1" {
+			if r.header != nil {
+				return board.Trace{}, r.errorf(text, "second parameter line, the first is on line %d", r.header.Line)
+			}
+			h, err := r.parseHeader(text, fields)
+			if err != nil {
+				return board.Trace{}, err
+			}
+			r.header = h
+			continue
+		}
+		return r.parseTrace(text, fields)
+	}
+	if err := r.sc.Err(); err != nil {
+		return board.Trace{}, err
+	}
+	return board.Trace{}, io.EOF
+}
+
+// Parse reads a whole trace; the parameter line is mandatory
+func Parse(in io.Reader) (*File, error) {
+	r := NewReader(in)
+	f := &File{}
+	for {
+		t, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.Traces = append(f.Traces, t)
+	}
+	if r.header == nil {
+		return nil, ErrNoHeader
+	}
+	f.Header = *r.header
+	return f, nil
+}
+
+func (r *Reader) errorf(text, format string, args ...any) error {
+	return &SyntaxError{Line: r.line, Text: text, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (r *Reader) parseHeader(text string, fields []string) (*Header, error) {
+	if len(fields) < 4 {
+		return nil, r.errorf(text, "parameter line needs \"-1 N W H\"")
+	}
+	h := &Header{Params: make(map[string]string), Line: r.line}
+	for i, p := range []*int{&h.Travelers, &h.Width, &h.Height} {
+		v, err := strconv.Atoi(fields[i+1])
+		if err != nil || v < 0 {
+			return nil, r.errorf(text, "%s %q is not a count", [...]string{"N", "W", "H"}[i], fields[i+1])
+		}
+		*p = v
+	}
+	for _, l := range strings.Split(strings.Join(fields[4:], " "), ";") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if key, value, ok := strings.Cut(l, "="); ok {
+			switch key {
+			case "WILD", "TRAPS":
+				ids, err := parseRange(value)
+				if err != nil {
+					return nil, r.errorf(text, "%s: %v", key, err)
+				}
+				if key == "WILD" {
+					h.Wild = ids
+				} else {
+					h.Traps = ids
+				}
+			default:
+				h.Params[key] = value
+			}
+		} else if len(h.Rows) < h.Height {
+			h.Rows = append(h.Rows, l)
+		} else {
+			h.Extra = append(h.Extra, l)
+		}
+	}
+	return h, nil
+}
+
+func (r *Reader) parseTrace(text string, fields []string) (board.Trace, error) {
+	var t board.Trace
+	if len(fields) != 5 {
+		return t, r.errorf(text, "want \"timestamp id x y symbol\", got %d fields", len(fields))
+	}
+	ts, err := parseTimeStamp(fields[0])
+	if err != nil {
+		return t, r.errorf(text, "timestamp %q: %v", fields[0], err)
+	}
+	t.TimeStamp = ts
+	for i, p := range []*int{&t.Id, &t.Position.X, &t.Position.Y} {
+		v, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			return t, r.errorf(text, "%s %q is not an integer", [...]string{"id", "x", "y"}[i], fields[i+1])
+		}
+		*p = v
+	}
+	sym, size := utf8.DecodeRuneInString(fields[4])
+	if size != len(fields[4]) || sym == utf8.RuneError {
+		return t, r.errorf(text, "symbol %q is not a single character", fields[4])
+	}
+	t.Symbol = sym
+	return t, nil
+}
+
+// parseTimeStamp accepts plain seconds and Go durations
+func parseTimeStamp(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "s") {
+		return time.ParseDuration(s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("neither seconds nor a Go duration")
+	}
+	return time.Duration(math.Round(v * float64(time.Second))), nil
+}
+
+// parseRange reads "first..last"
+func parseRange(s string) (IDRange, error) {
+	lo, hi, ok := strings.Cut(s, "..")
+	first, err1 := strconv.Atoi(lo)
+	last, err2 := strconv.Atoi(hi)
+	if !ok || err1 != nil || err2 != nil || last < first-1 {
+		return IDRange{}, fmt.Errorf("%q is not an ID range first..last", s)
+	}
+	return IDRange{First: first, Count: last - first + 1}, nil
+}
@@ -0,0 +1,127 @@
+package trace
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+func TestParseTraceLines(t *testing.T) {
+	tests := []struct {
+		producer string
+		line     string
+		want     board.Trace
+	}{
+		{"go1, a Go duration", "31.836383ms 6 1 3 G",
+			board.Trace{TimeStamp: 31836383 * time.Nanosecond, Id: 6, Position: board.Position{X: 1, Y: 3}, Symbol: 'G'}},
+		{"go2, zad2, zad4, padded seconds", "0.003097 20  9  1 6",
+			board.Trace{TimeStamp: 3097 * time.Microsecond, Id: 20, Position: board.Position{X: 9, Y: 1}, Symbol: '6'}},
+		{"Dekker, leading space", " 1.631208744 1 1 0  B",
+			board.Trace{TimeStamp: 1631208744 * time.Nanosecond, Id: 1, Position: board.Position{X: 1, Y: 0}, Symbol: 'B'}},
+		{"Peterson, seconds with a unit", "1.480031511s 0 0 0 A",
+			board.Trace{TimeStamp: 1480031511 * time.Nanosecond, Id: 0, Position: board.Position{X: 0, Y: 0}, Symbol: 'A'}},
+		{"zad4, a trap", "0.000000000 -3 4 2 #",
+			board.Trace{Id: -3, Position: board.Position{X: 4, Y: 2}, Symbol: '#'}},
+		{"canonical", "0.003097000 20 9 1 6",
+			board.Trace{TimeStamp: 3097 * time.Microsecond, Id: 20, Position: board.Position{X: 9, Y: 1}, Symbol: '6'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.producer, func(t *testing.T) {
+			got, err := NewReader(strings.NewReader(tt.line)).Next()
+			if err != nil {
+				t.Fatalf("Next(%q): %v", tt.line, err)
+			}
+			if got != tt.want {
+				t.Errorf("Next(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		producer string
+		line     string
+		want     Header
+	}{
+		{"bakery", "-1 15 15 4 LOCAL_SECTION;ENTRY_PROTOCOL;CRITICAL_SECTION;EXIT_PROTOCOL;MAX_TICKET=240;SEED=5;",
+			Header{Travelers: 15, Width: 15, Height: 4,
+				Rows:   []string{"LOCAL_SECTION", "ENTRY_PROTOCOL", "CRITICAL_SECTION", "EXIT_PROTOCOL"},
+				Params: map[string]string{"MAX_TICKET": "240", "SEED": "5"}}},
+		{"Dekker, an extra label", "-1 2 2 4 LOCAL_SECTION;ENTRY_PROTOCOL;CRITICAL_SECTION;EXIT_PROTOCOL;EXTRA_LABEL;",
+			Header{Travelers: 2, Width: 2, Height: 4,
+				Rows:   []string{"LOCAL_SECTION", "ENTRY_PROTOCOL", "CRITICAL_SECTION", "EXIT_PROTOCOL"},
+				Params: map[string]string{}, Extra: []string{"EXTRA_LABEL"}}},
+		{"go1, no labels", "-1 15 15 15",
+			Header{Travelers: 15, Width: 15, Height: 15, Params: map[string]string{}}},
+		{"zad4, ID ranges", "-1 15 15 15 SEED=7;TRAPS=-15..-1;WILD=15..24;",
+			Header{Travelers: 15, Width: 15, Height: 15,
+				Traps: IDRange{First: -15, Count: 15}, Wild: IDRange{First: 15, Count: 10},
+				Params: map[string]string{"SEED": "7"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.producer, func(t *testing.T) {
+			f, err := Parse(strings.NewReader(tt.line))
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			tt.want.Line = 1
+			if !reflect.DeepEqual(f.Header, tt.want) {
+				t.Errorf("Parse(%q) header = %+v, want %+v", tt.line, f.Header, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHiddenWildTenant(t *testing.T) {
+	// zad2: wild tenant 15 appears, then its life ends at the hidden (W, H)
+	in := "0.100000000 15 3 4 7\n" +
+		"0.900000000 15 10 10 7\n" +
+		"-1 15 10 10 WILD=15..24;\n"
+	f, err := Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := f.Header.Kind(15); k != KindWild {
+		t.Errorf("Kind(15) = %s, want %s", k, KindWild)
+	}
+	if f.Header.Hidden(f.Traces[0].Position) {
+		t.Errorf("(3,4) is hidden on a 10x10 board")
+	}
+	if !f.Header.Hidden(f.Traces[1].Position) {
+		t.Errorf("(10,10) is not hidden on a 10x10 board")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		line int // of the SyntaxError, 0 for ErrNoHeader
+	}{
+		{"no parameter line", "0.1 0 0 0 A\n", 0},
+		{"four fields", "0.1 0 0 0\n-1 1 1 1\n", 1},
+		{"bad timestamp", "\n0.1x 0 0 0 A\n-1 1 1 1\n", 2},
+		{"two symbols", "0.1 0 0 0 AB\n-1 1 1 1\n", 1},
+		{"second parameter line", "-1 1 1 1\n-1 1 1 1\n", 2},
+		{"bad ID range", "-1 1 1 1 WILD=3..x\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.in))
+			var se *SyntaxError
+			switch {
+			case tt.line == 0 && !errors.Is(err, ErrNoHeader):
+				t.Errorf("Parse(%q) = %v, want ErrNoHeader", tt.in, err)
+			case tt.line > 0 && !errors.As(err, &se):
+				t.Errorf("Parse(%q) = %v, want a SyntaxError", tt.in, err)
+			case tt.line > 0 && se.Line != tt.line:
+				t.Errorf("Parse(%q) error on line %d, want %d", tt.in, se.Line, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Package trace reads the text traces printed by the simulations.
+//
+// A trace is a sequence of "timestamp id x y symbol" lines and one
+// parameter line "-1 N W H [labels]", printed last by the Go programs.
+// The programs disagree on the details, and every dialect is accepted:
+//
+//	31.836383ms 6 1 3 G             go1, a Go duration
+//	0.003097 20  9  1 6             go2, zad2, zad4, %8.6f seconds, padded
+//	 1.631208744 1 1 0  B           Dekker, leading space, two before the symbol
+//	1.480031511s 0 0 0 A            Peterson, seconds with a unit
+//	-1 15 15 4 LOCAL_SECTION;ENTRY_PROTOCOL;CRITICAL_SECTION;EXIT_PROTOCOL;MAX_TICKET=240;SEED=5;
+//
+// The labels of the parameter line are split into the row labels of the
+// lista3 programs, KEY=value parameters and the remaining extra labels.
+//
+// The programs now all print the canonical format of Format and
+// WriteHeader: seconds with nine decimals, single spaces, and the
+// parameter line last, documenting the IDs of wild tenants and traps:
+//
+//	0.003097000 20 9 1 6
+//	-1 15 15 15 SEED=7;TRAPS=-15..-1;WILD=15..24;
+//
+// With -format jsonl the programs print JSON Lines instead, see Writer.
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+// Header holds the parameter line of a trace
+type Header struct {
+	Travelers int // N, the entities 0 to N-1 are travelers or processes
+	Width     int
+	Height    int
+
+	Rows   []string          // labels of the board rows, lista3 states
+	Wild   IDRange           // wild tenants, WILD=first..last
+	Traps  IDRange           // traps, TRAPS=first..last
+	Params map[string]string // other KEY=value labels, like MAX_TICKET and SEED
+	Extra  []string          // any other label, like EXTRA_LABEL
+	Line   int               // line of the trace it was read from, 0 if not read
+}
+
+// IDRange is a range of consecutive entity IDs
+type IDRange struct {
+	First int
+	Count int
+}
+
+// Contains reports whether id is in the range
+func (r IDRange) Contains(id int) bool {
+	return id >= r.First && id < r.First+r.Count
+}
+
+func (r IDRange) String() string {
+	return fmt.Sprintf("%d..%d", r.First, r.First+r.Count-1)
+}
+
+// Kind of entity behind an ID
+type Kind string
+
+const (
+	KindTraveler Kind = "traveler"
+	KindWild     Kind = "wild"
+	KindTrap     Kind = "trap"
+	KindProcess  Kind = "process"
+	KindUnknown  Kind = "unknown"
+)
+
+// Kind tells what the entity with the given ID is; the entities 0 to N-1
+// are processes when the rows are labelled, travelers otherwise
+func (h *Header) Kind(id int) Kind {
+	switch {
+	case id >= 0 && id < h.Travelers && len(h.Rows) > 0:
+		return KindProcess
+	case id >= 0 && id < h.Travelers:
+		return KindTraveler
+	case h.Wild.Contains(id):
+		return KindWild
+	case h.Traps.Contains(id):
+		return KindTrap
+	// traces printed before the ranges were documented
+	case h.Wild.Count == 0 && id >= h.Travelers:
+		return KindWild
+	case h.Traps.Count == 0 && id < 0:
+		return KindTrap
+	}
+	return KindUnknown
+}
+
+// Param returns an integer KEY=value label of the header
+func (h *Header) Param(key string) (int64, bool) {
+	v, err := strconv.ParseInt(h.Params[key], 10, 64)
+	return v, err == nil
+}
+
+// Hidden reports whether p is the hidden position (W, H), where wild
+// tenants go when their life ends and go3 travelers when they finish and
+// free their cell
+func (h *Header) Hidden(p board.Position) bool {
+	return p.X == h.Width && p.Y == h.Height
+}
+
+// File is a whole trace
+type File struct {
+	Header Header
+	Traces []board.Trace // in the order of the file
+}
+
+// Sort orders the traces by time stamp, keeping the file order of equal ones
+func (f *File) Sort() {
+	sort.SliceStable(f.Traces, func(i, j int) bool {
+		return f.Traces[i].TimeStamp < f.Traces[j].TimeStamp
+	})
+}
+
+// Interval is the time an entity spent at one position with one symbol,
+// for the lista3 processes the time spent in one state
+type Interval struct {
+	Id       int
+	Position board.Position
+	Symbol   rune
+	Start    time.Duration
+	End      time.Duration
+}
+
+// End returns the time stamp of the last trace
+func (f *File) End() time.Duration {
+	var end time.Duration
+	for _, t := range f.Traces {
+		end = max(end, t.TimeStamp)
+	}
+	return end
+}
+
+// Intervals cuts the life of every entity at its traces: an interval
+// lasts from one trace of the entity to its next one, the last one until
+// the end of the trace. They are grouped by entity, in ID order, and
+// ordered by time within an entity.
+func (f *File) Intervals() []Interval {
+	byId := make(map[int][]board.Trace)
+	for _, t := range f.Traces {
+		byId[t.Id] = append(byId[t.Id], t)
+	}
+	ids := make([]int, 0, len(byId))
+	for id := range byId {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	end := f.End()
+	var out []Interval
+	for _, id := range ids {
+		ts := byId[id]
+		sort.SliceStable(ts, func(i, j int) bool { return ts[i].TimeStamp < ts[j].TimeStamp })
+		for i, t := range ts {
+			iv := Interval{Id: id, Position: t.Position, Symbol: t.Symbol, Start: t.TimeStamp, End: end}
+			if i+1 < len(ts) {
+				iv.End = ts[i+1].TimeStamp
+			}
+			out = append(out, iv)
+		}
+	}
+	return out
+}
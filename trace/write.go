@@ -0,0 +1,188 @@
+package trace
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+)
+
+// Format writes t as a canonical trace line; it is the board.FormatFunc
+// of every program
+func Format(w io.Writer, t board.Trace) {
+	fmt.Fprintf(w, "%.9f %d %d %d %c\n",
+		t.TimeStamp.Seconds(), t.Id, t.Position.X, t.Position.Y, t.Symbol)
+}
+
+// WriteHeader writes the canonical parameter line: "-1 N W H", then the
+// row labels, the extra labels and the KEY=value labels in key order,
+// each followed by a semicolon
+func WriteHeader(w io.Writer, h Header) error {
+	_, err := fmt.Fprintf(w, "-1 %d %d %d %s\n", h.Travelers, h.Width, h.Height, h.labels())
+	return err
+}
+
+func (h *Header) labels() string {
+	params := make([]string, 0, len(h.Params)+2)
+	for key, value := range h.Params {
+		params = append(params, key+"="+value)
+	}
+	if h.Wild.Count > 0 {
+		params = append(params, "WILD="+h.Wild.String())
+	}
+	if h.Traps.Count > 0 {
+		params = append(params, "TRAPS="+h.Traps.String())
+	}
+	sort.Strings(params)
+
+	var b strings.Builder
+	for _, group := range [][]string{h.Rows, h.Extra, params} {
+		for _, l := range group {
+			b.WriteString(l + ";")
+		}
+	}
+	return b.String()
+}
+
+// Output formats accepted by NewWriter
+const (
+	FormatText  = "text"
+	FormatJSONL = "jsonl"
+)
+
+// RegisterFormat defines the -format flag on fs
+func RegisterFormat(fs *flag.FlagSet) *string {
+	return fs.String("format", FormatText,
+		"text: \"timestamp id x y symbol\" lines and a -1 parameter line; jsonl: one JSON object per line")
+}
+
+// Writer prints a whole trace in one of the output formats. In JSON
+// Lines the first object describes the run, every trace is an object
+// of type "event" and a last "summary" object carries the parameters
+// only known at the end, like MAX_TICKET.
+type Writer struct {
+	out    io.Writer
+	format string
+	header Header
+}
+
+// NewWriter starts a trace of the run described by h
+func NewWriter(out io.Writer, format string, h Header) (*Writer, error) {
+	h.Params = maps.Clone(h.Params) // the caller may add to its own later
+	w := &Writer{out: out, format: format, header: h}
+	switch format {
+	case FormatText:
+		return w, nil
+	case FormatJSONL:
+		return w, w.encode(newJSONMeta(h))
+	}
+	return nil, fmt.Errorf("unknown trace format %q, use %q or %q", format, FormatText, FormatJSONL)
+}
+
+// Format writes a single trace to out; it is a board.FormatFunc
+func (w *Writer) Format(out io.Writer, t board.Trace) {
+	if w.format == FormatText {
+		Format(out, t)
+		return
+	}
+	json.NewEncoder(out).Encode(jsonEvent{
+		Type:      "event",
+		TimeStamp: t.TimeStamp.Seconds(),
+		Id:        t.Id,
+		Entity:    w.header.Kind(t.Id),
+		X:         t.Position.X,
+		Y:         t.Position.Y,
+		Symbol:    string(t.Symbol),
+		Event:     t.Event,
+	})
+}
+
+// End finishes the trace; h is the final header, which may carry
+// parameters unknown at the start
+func (w *Writer) End(h Header) error {
+	if w.format == FormatText {
+		return WriteHeader(w.out, h)
+	}
+	late := make(map[string]string)
+	for key, value := range h.Params {
+		if w.header.Params[key] != value {
+			late[key] = value
+		}
+	}
+	if len(late) == 0 {
+		return nil
+	}
+	return w.encode(jsonSummary{Type: "summary", Params: late})
+}
+
+func (w *Writer) encode(v any) error {
+	return json.NewEncoder(w.out).Encode(v)
+}
+
+type jsonEvent struct {
+	Type      string      `json:"type"`
+	TimeStamp float64     `json:"timestamp"`
+	Id        int         `json:"id"`
+	Entity    Kind        `json:"entity"`
+	X         int         `json:"x"`
+	Y         int         `json:"y"`
+	Symbol    string      `json:"symbol"`
+	Event     board.Event `json:"event,omitempty"`
+}
+
+type jsonRange struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+type jsonMeta struct {
+	Type      string            `json:"type"`
+	Travelers int               `json:"travelers"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Seed      *int64            `json:"seed,omitempty"`
+	Labels    []string          `json:"labels,omitempty"`
+	Extra     []string          `json:"extra,omitempty"`
+	Wild      *jsonRange        `json:"wild,omitempty"`
+	Traps     *jsonRange        `json:"traps,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+}
+
+type jsonSummary struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params"`
+}
+
+func newJSONMeta(h Header) jsonMeta {
+	m := jsonMeta{
+		Type:      "metadata",
+		Travelers: h.Travelers,
+		Width:     h.Width,
+		Height:    h.Height,
+		Labels:    h.Rows,
+		Extra:     h.Extra,
+		Params:    make(map[string]string),
+	}
+	for key, value := range h.Params {
+		m.Params[key] = value
+	}
+	if seed, ok := h.Param("SEED"); ok {
+		m.Seed = &seed
+		delete(m.Params, "SEED")
+	}
+	m.Wild = newJSONRange(h.Wild)
+	m.Traps = newJSONRange(h.Traps)
+	return m
+}
+
+func newJSONRange(r IDRange) *jsonRange {
+	if r.Count == 0 {
+		return nil
+	}
+	return &jsonRange{First: r.First, Last: r.First + r.Count - 1}
+}
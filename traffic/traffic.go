@@ -0,0 +1,177 @@
+// Package traffic measures how the entities of a board trace used the
+// cells of the torus: how often they were entered, how long they were
+// held, how many requests found them taken and how many catches their
+// traps made. It tells whether the random walk covers the board
+// uniformly and where congestion leaves travelers deadlocked.
+package traffic
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/TrollYuck/PW_INA_2025/board"
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+// Cell is the traffic of one cell
+type Cell struct {
+	Visits    int           // arrivals of travelers and wild tenants
+	Occupancy time.Duration // time travelers and wild tenants stood on it, summed over them
+	Refused   int64         // requests that found it taken, from the CONTENTION parameter
+	TrapHits  int           // catches reported by its trap
+	Stuck     int           // travelers that ended the run on it in lowercase, or left the board from it
+}
+
+// Map is the traffic of every cell of a trace
+type Map struct {
+	Width, Height int
+	Cells         [][]Cell // [y][x]
+	Contention    bool     // the trace has the CONTENTION parameter, go2, zad2 and zad4 print it
+}
+
+// Analyze measures the traffic of a board trace. An entity holds its
+// cell until its next trace: a go3 traveler frees its last cell when it
+// leaves the board for the hidden position, in the other programs a
+// traveler keeps it to the end of the run. Text traces have no events,
+// so a traveler stuck in zad4 may have been trapped next to the cell
+// rather than deadlocked on it.
+func Analyze(f *trace.File) (*Map, error) {
+	h := &f.Header
+	if len(h.Rows) > 0 {
+		return nil, fmt.Errorf("trace has row labels, it is not a board trace")
+	}
+	if h.Width <= 0 || h.Height <= 0 {
+		return nil, fmt.Errorf("trace has no board size")
+	}
+	m := &Map{Width: h.Width, Height: h.Height, Cells: make([][]Cell, h.Height)}
+	for y := range m.Cells {
+		m.Cells[y] = make([]Cell, h.Width)
+	}
+
+	intervals := f.Intervals() // by entity, in time order
+	for i, iv := range intervals {
+		c := m.cell(iv.Position)
+		if c == nil {
+			continue // hidden
+		}
+		first := i == 0 || intervals[i-1].Id != iv.Id
+		// go3 travelers leave the board for the hidden position at the end
+		last := i+1 == len(intervals) || intervals[i+1].Id != iv.Id || h.Hidden(intervals[i+1].Position)
+		switch h.Kind(iv.Id) {
+		case trace.KindTrap:
+			// a trap reports once set and again after every catch
+			if !first {
+				c.TrapHits++
+			}
+			continue
+		case trace.KindTraveler:
+			if last && unicode.IsLower(iv.Symbol) {
+				c.Stuck++
+			}
+		}
+		if first || intervals[i-1].Position != iv.Position {
+			c.Visits++
+		}
+		c.Occupancy += iv.End - iv.Start
+	}
+
+	if s, ok := h.Params["CONTENTION"]; ok {
+		contention, err := board.ParseContention(s)
+		if err != nil {
+			return nil, err
+		}
+		for p, n := range contention {
+			if c := m.cell(p); c != nil {
+				c.Refused += n
+			}
+		}
+		m.Contention = true
+	}
+	return m, nil
+}
+
+// cell returns the cell at p, nil for a position off the board
+func (m *Map) cell(p board.Position) *Cell {
+	if p.X < 0 || p.X >= m.Width || p.Y < 0 || p.Y >= m.Height {
+		return nil
+	}
+	return &m.Cells[p.Y][p.X]
+}
+
+// Metric is one of the numbers of a Cell
+type Metric struct {
+	Name  string
+	Value func(c *Cell) float64
+}
+
+// Metrics are the numbers of a Cell, in the order of the CSV columns
+var Metrics = []Metric{
+	{"visits", func(c *Cell) float64 { return float64(c.Visits) }},
+	{"occupancy", func(c *Cell) float64 { return c.Occupancy.Seconds() }},
+	{"refused", func(c *Cell) float64 { return float64(c.Refused) }},
+	{"traps", func(c *Cell) float64 { return float64(c.TrapHits) }},
+	{"stuck", func(c *Cell) float64 { return float64(c.Stuck) }},
+}
+
+// MetricNamed returns the metric with the given name
+func MetricNamed(name string) (Metric, error) {
+	names := make([]string, len(Metrics))
+	for i, mt := range Metrics {
+		if mt.Name == name {
+			return mt, nil
+		}
+		names[i] = mt.Name
+	}
+	return Metric{}, fmt.Errorf("unknown metric %q, use one of %s", name, strings.Join(names, ", "))
+}
+
+// Range returns the total and the highest value of mt over the cells
+func (m *Map) Range(mt Metric) (total, highest float64) {
+	for y := range m.Cells {
+		for x := range m.Cells[y] {
+			v := mt.Value(&m.Cells[y][x])
+			total += v
+			highest = max(highest, v)
+		}
+	}
+	return total, highest
+}
+
+// Uniformity is Pearson's chi-squared test of the visits against a
+// uniform spread over the cells
+type Uniformity struct {
+	ChiSquared float64
+	Freedom    int     // degrees of freedom, cells - 1
+	P          float64 // probability of a spread at least that uneven if the walk were uniform
+}
+
+func (u Uniformity) String() string {
+	return fmt.Sprintf("chi-squared %.1f with %d degrees of freedom, p = %.3f", u.ChiSquared, u.Freedom, u.P)
+}
+
+// Uniformity tests whether the visits are spread uniformly; p is the
+// Wilson-Hilferty approximation, good for the sizes of the boards. The
+// steps of a walk are not independent, so a short run looks uneven even
+// when the walk is uniform: compare runs with many steps.
+func (m *Map) Uniformity() Uniformity {
+	total, _ := m.Range(Metrics[0])
+	cells := m.Width * m.Height
+	u := Uniformity{Freedom: cells - 1, P: 1}
+	if total == 0 || cells < 2 {
+		return u
+	}
+	expected := total / float64(cells)
+	for y := range m.Cells {
+		for x := range m.Cells[y] {
+			d := float64(m.Cells[y][x].Visits) - expected
+			u.ChiSquared += d * d / expected
+		}
+	}
+	k := float64(u.Freedom)
+	z := (math.Cbrt(u.ChiSquared/k) - (1 - 2/(9*k))) / math.Sqrt(2/(9*k))
+	u.P = math.Erfc(z/math.Sqrt2) / 2
+	return u
+}
@@ -0,0 +1,50 @@
+package traffic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrollYuck/PW_INA_2025/trace"
+)
+
+func analyze(t *testing.T, in string) *Map {
+	t.Helper()
+	f, err := trace.Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Analyze(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestOccupancyEnds(t *testing.T) {
+	// go3: A finishes on (1,1) at 0.3s and leaves the board, B on (2,2)
+	// deadlocks at 0.2s and leaves too, C keeps (3,3) to the end at 1s
+	m := analyze(t, ""+
+		"0.1 0 0 1 A\n0.2 0 1 1 A\n0.3 0 4 4 A\n"+
+		"0.1 1 2 2 B\n0.2 1 2 2 b\n0.5 1 4 4 b\n"+
+		"0.1 2 3 3 C\n1.0 2 3 2 C\n"+
+		"-1 3 4 4\n")
+	tests := []struct {
+		x, y      int
+		occupancy time.Duration
+		visits    int
+		stuck     int
+	}{
+		{1, 1, 100 * time.Millisecond, 1, 0},
+		{2, 2, 400 * time.Millisecond, 1, 1},
+		{3, 3, 900 * time.Millisecond, 1, 0},
+		{3, 2, 0, 1, 0},
+	}
+	for _, tt := range tests {
+		c := m.Cells[tt.y][tt.x]
+		if c.Occupancy != tt.occupancy || c.Visits != tt.visits || c.Stuck != tt.stuck {
+			t.Errorf("cell (%d,%d) = %+v, want occupancy %v, %d visits, %d stuck",
+				tt.x, tt.y, c, tt.occupancy, tt.visits, tt.stuck)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package traffic
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ramp shades the cells of the ASCII heatmap, each character a ninth of
+// the highest value more
+const ramp = ".:-=+*#%@"
+
+// WriteASCII draws mt as a heatmap, two characters per cell: blank for
+// zero, then the characters of ramp up to the highest value
+func WriteASCII(w io.Writer, m *Map, mt Metric) error {
+	b := bufio.NewWriter(w)
+	total, highest := m.Range(mt)
+	fmt.Fprintf(b, "%s: total %s, highest %s, scale \"%s\"\n", mt.Name, number(total), number(highest), ramp)
+	for y := range m.Cells {
+		for x := range m.Cells[y] {
+			v := mt.Value(&m.Cells[y][x])
+			if v == 0 {
+				b.WriteString("  ")
+				continue
+			}
+			k := int(math.Ceil(v/highest*float64(len(ramp)))) - 1
+			b.WriteString(string([]byte{ramp[k], ramp[k]}))
+		}
+		b.WriteByte('\n')
+	}
+	return b.Flush()
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// WriteCSV writes a line per cell, in row order: x, y and every metric
+func WriteCSV(w io.Writer, m *Map) error {
+	out := csv.NewWriter(w)
+	head := []string{"x", "y"}
+	for _, mt := range Metrics {
+		head = append(head, mt.Name)
+	}
+	out.Write(head)
+	for y := range m.Cells {
+		for x := range m.Cells[y] {
+			rec := []string{strconv.Itoa(x), strconv.Itoa(y)}
+			for _, mt := range Metrics {
+				rec = append(rec, number(mt.Value(&m.Cells[y][x])))
+			}
+			out.Write(rec)
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// heat are the colours of the PNG heatmap from low to high, a zero is
+// drawn in the colour of the grid
+var heat = []color.RGBA{
+	{0x1a, 0x1a, 0x5e, 0xff},
+	{0xb0, 0x2a, 0x6a, 0xff},
+	{0xf0, 0x80, 0x20, 0xff},
+	{0xff, 0xf0, 0x80, 0xff},
+}
+
+var (
+	gridColor = color.RGBA{0x11, 0x11, 0x11, 0xff}
+	zeroColor = color.RGBA{0x30, 0x30, 0x30, 0xff}
+)
+
+// WritePNG draws mt as a heatmap, cell pixels per cell
+func WritePNG(w io.Writer, m *Map, mt Metric, cell int) error {
+	img := image.NewRGBA(image.Rect(0, 0, m.Width*cell+1, m.Height*cell+1))
+	fill := func(r image.Rectangle, c color.RGBA) {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+	fill(img.Rect, gridColor)
+	_, highest := m.Range(mt)
+	for y := range m.Cells {
+		for x := range m.Cells[y] {
+			c := zeroColor
+			if v := mt.Value(&m.Cells[y][x]); v > 0 {
+				c = shade(v / highest)
+			}
+			fill(image.Rect(x*cell+1, y*cell+1, (x+1)*cell, (y+1)*cell), c)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// shade interpolates heat at f, from 0 to 1
+func shade(f float64) color.RGBA {
+	f *= float64(len(heat) - 1)
+	i := min(int(f), len(heat)-2)
+	t := f - float64(i)
+	a, b := heat[i], heat[i+1]
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + t*(float64(y)-float64(x))) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}